@@ -0,0 +1,196 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+)
+
+// Identity 表示 ssh-agent 中已加载的一个身份
+type Identity struct {
+	PublicKey   ssh.PublicKey
+	Type        string
+	Fingerprint string
+	Comment     string
+}
+
+// AddOptions 添加密钥时的约束条件
+type AddOptions struct {
+	Lifetime time.Duration
+	Confirm  bool
+}
+
+// maxLifetime 是协议中以 uint32 秒表示的最长有效期
+const maxLifetime = time.Duration(math.MaxUint32) * time.Second
+
+// ParseLifetime 解析 1h、30m 形式的有效期；agent 以整秒计时，因此拒绝不足 1 秒和非正数的值
+func ParseLifetime(value string) (time.Duration, error) {
+	lifetime, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("无效的有效期: %s", value)
+	}
+	if lifetime == 0 {
+		return 0, fmt.Errorf("有效期必须大于 0，不限制时请留空")
+	}
+	if err := checkLifetime(lifetime); err != nil {
+		return 0, err
+	}
+	return lifetime, nil
+}
+
+// checkLifetime 检查有效期能否用 uint32 秒表示，0 表示不限
+func checkLifetime(lifetime time.Duration) error {
+	switch {
+	case lifetime < 0:
+		return fmt.Errorf("有效期不能为负数")
+	case lifetime > 0 && lifetime < time.Second:
+		return fmt.Errorf("有效期不能少于 1 秒")
+	case lifetime > maxLifetime:
+		return fmt.Errorf("有效期过长")
+	}
+	return nil
+}
+
+// Client 封装与 ssh-agent 的交互
+type Client struct {
+	agent sshagent.ExtendedAgent
+	conn  io.ReadWriteCloser
+}
+
+// NewClient 基于已有的 agent 实现创建客户端，便于接入进程内的 agent
+func NewClient(a sshagent.ExtendedAgent) *Client {
+	return &Client{agent: a}
+}
+
+// Dial 连接 SSH_AUTH_SOCK 指向的 ssh-agent；Windows 上未设置时连接 OpenSSH 服务的命名管道
+func Dial() (*Client, error) {
+	conn, err := dialAgent()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{agent: sshagent.NewClient(conn), conn: conn}, nil
+}
+
+// Close 关闭与 agent 的连接
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Agent 返回底层的 agent 接口，用于 SSH 认证
+func (c *Client) Agent() sshagent.ExtendedAgent {
+	return c.agent
+}
+
+// List 列出 agent 中已加载的身份
+func (c *Client) List() ([]Identity, error) {
+	list, err := c.agent.List()
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 agent 身份列表: %w", err)
+	}
+
+	identities := make([]Identity, 0, len(list))
+	for _, key := range list {
+		pub, err := ssh.ParsePublicKey(key.Marshal())
+		if err != nil {
+			continue
+		}
+		identities = append(identities, Identity{
+			PublicKey:   pub,
+			Type:        key.Type(),
+			Fingerprint: ssh.FingerprintSHA256(pub),
+			Comment:     key.Comment,
+		})
+	}
+	return identities, nil
+}
+
+// AddKeyFile 将私钥文件加入 agent，同目录下存在证书时一并加入
+func (c *Client) AddKeyFile(path string, passphrase []byte, opts AddOptions) error {
+	if err := checkLifetime(opts.Lifetime); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("无法读取私钥: %w", err)
+	}
+	key, err := keys.ParsePrivateKey(data, passphrase)
+	if err != nil {
+		return err
+	}
+
+	comment := path
+	if info, err := keys.Inspect(path); err == nil && info.Comment != "" {
+		comment = info.Comment
+	}
+	added := sshagent.AddedKey{
+		PrivateKey:       key,
+		Comment:          comment,
+		LifetimeSecs:     uint32(opts.Lifetime / time.Second),
+		ConfirmBeforeUse: opts.Confirm,
+	}
+	if err := c.agent.Add(added); err != nil {
+		return fmt.Errorf("无法添加密钥: %w", err)
+	}
+
+	// 与 ssh-add 一样，存在 -cert.pub 时同时加入证书
+	if cert, err := keys.ParseCertificate(keys.CertPathFor(path)); err == nil {
+		added.Certificate = cert.Cert
+		if err := c.agent.Add(added); err != nil {
+			return fmt.Errorf("无法添加证书: %w", err)
+		}
+	}
+	return nil
+}
+
+// Remove 从 agent 中移除指定身份
+func (c *Client) Remove(pub ssh.PublicKey) error {
+	if err := c.agent.Remove(pub); err != nil {
+		return fmt.Errorf("无法移除身份: %w", err)
+	}
+	return nil
+}
+
+// RemoveAll 移除 agent 中的所有身份
+func (c *Client) RemoveAll() error {
+	if err := c.agent.RemoveAll(); err != nil {
+		return fmt.Errorf("无法移除身份: %w", err)
+	}
+	return nil
+}
+
+// Lock 使用口令锁定 agent
+func (c *Client) Lock(passphrase []byte) error {
+	if err := c.agent.Lock(passphrase); err != nil {
+		return fmt.Errorf("无法锁定 agent: %w", err)
+	}
+	return nil
+}
+
+// Unlock 解锁 agent
+func (c *Client) Unlock(passphrase []byte) error {
+	if err := c.agent.Unlock(passphrase); err != nil {
+		return fmt.Errorf("无法解锁 agent: %w", err)
+	}
+	return nil
+}
+
+// LoadedFingerprints 返回已加载身份的指纹集合，证书同时记录其内含公钥的指纹
+func LoadedFingerprints(identities []Identity) map[string]bool {
+	loaded := map[string]bool{}
+	for _, identity := range identities {
+		loaded[identity.Fingerprint] = true
+		if cert, ok := identity.PublicKey.(*ssh.Certificate); ok {
+			loaded[ssh.FingerprintSHA256(cert.Key)] = true
+		}
+	}
+	return loaded
+}
@@ -0,0 +1,200 @@
+package agent
+
+import (
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+)
+
+// recordingAgent 记录 Add 收到的约束，NewKeyring 不会暴露这些信息
+type recordingAgent struct {
+	sshagent.Agent
+	mu    sync.Mutex
+	added []sshagent.AddedKey
+}
+
+func (a *recordingAgent) Add(key sshagent.AddedKey) error {
+	a.mu.Lock()
+	a.added = append(a.added, key)
+	a.mu.Unlock()
+	return a.Agent.Add(key)
+}
+
+func (a *recordingAgent) last() sshagent.AddedKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.added[len(a.added)-1]
+}
+
+// serveAgent 在临时 unix socket 上提供进程内的 agent，并设置 SSH_AUTH_SOCK
+func serveAgent(t *testing.T) *recordingAgent {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("无法创建 unix socket: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	ring := &recordingAgent{Agent: sshagent.NewKeyring()}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				sshagent.ServeAgent(ring, conn)
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", socket)
+	return ring
+}
+
+// newKeyFile 生成一个无口令的 ED25519 私钥
+func newKeyFile(t *testing.T, name string) (string, ssh.PublicKey) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	pub, err := keys.Generate(path, keys.GenerateEd25519, name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, pub
+}
+
+func dial(t *testing.T) *Client {
+	t.Helper()
+	client, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestAddListRemove(t *testing.T) {
+	ring := serveAgent(t)
+	client := dial(t)
+
+	path1, pub1 := newKeyFile(t, "one")
+	path2, pub2 := newKeyFile(t, "two")
+	if err := client.AddKeyFile(path1, nil, AddOptions{Lifetime: time.Hour, Confirm: true}); err != nil {
+		t.Fatal(err)
+	}
+	added := ring.last()
+	if added.LifetimeSecs != 3600 || !added.ConfirmBeforeUse {
+		t.Errorf("约束 = lifetime %d confirm %v，期望 3600 true", added.LifetimeSecs, added.ConfirmBeforeUse)
+	}
+	if err := client.AddKeyFile(path2, nil, AddOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	added = ring.last()
+	if added.LifetimeSecs != 0 || added.ConfirmBeforeUse {
+		t.Errorf("未设置约束时 = lifetime %d confirm %v", added.LifetimeSecs, added.ConfirmBeforeUse)
+	}
+
+	identities, err := client.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(identities) != 2 {
+		t.Fatalf("身份数量 = %d，期望 2", len(identities))
+	}
+	if identities[0].Comment != "one" || identities[0].Fingerprint != ssh.FingerprintSHA256(pub1) {
+		t.Errorf("第一个身份 = %+v", identities[0])
+	}
+	loaded := LoadedFingerprints(identities)
+	if !loaded[ssh.FingerprintSHA256(pub1)] || !loaded[ssh.FingerprintSHA256(pub2)] {
+		t.Errorf("LoadedFingerprints 缺少已加载的密钥: %v", loaded)
+	}
+
+	if err := client.Remove(pub1); err != nil {
+		t.Fatal(err)
+	}
+	identities, _ = client.List()
+	if len(identities) != 1 || identities[0].Fingerprint != ssh.FingerprintSHA256(pub2) {
+		t.Fatalf("移除后 = %+v", identities)
+	}
+	if err := client.Remove(pub1); err == nil {
+		t.Error("重复移除应返回错误")
+	}
+
+	if err := client.AddKeyFile(path1, nil, AddOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveAll(); err != nil {
+		t.Fatal(err)
+	}
+	if identities, _ = client.List(); len(identities) != 0 {
+		t.Fatalf("RemoveAll 后仍有 %d 个身份", len(identities))
+	}
+}
+
+func TestLifetimeExpires(t *testing.T) {
+	serveAgent(t)
+	client := dial(t)
+
+	path, _ := newKeyFile(t, "short")
+	if err := client.AddKeyFile(path, nil, AddOptions{Lifetime: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	if identities, _ := client.List(); len(identities) != 1 {
+		t.Fatalf("身份数量 = %d，期望 1", len(identities))
+	}
+	time.Sleep(1100 * time.Millisecond)
+	if identities, _ := client.List(); len(identities) != 0 {
+		t.Fatalf("有效期过后仍有 %d 个身份", len(identities))
+	}
+}
+
+func TestLifetimeRejected(t *testing.T) {
+	ring := serveAgent(t)
+	client := dial(t)
+	path, _ := newKeyFile(t, "bad")
+
+	for _, lifetime := range []time.Duration{-time.Hour, 500 * time.Millisecond, maxLifetime + time.Second} {
+		if err := client.AddKeyFile(path, nil, AddOptions{Lifetime: lifetime}); err == nil {
+			t.Errorf("有效期 %v 应被拒绝", lifetime)
+		}
+	}
+	if len(ring.added) != 0 {
+		t.Errorf("被拒绝的有效期不应发送到 agent")
+	}
+}
+
+func TestParseLifetime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"1h", time.Hour, true},
+		{"90s", 90 * time.Second, true},
+		{"1s", time.Second, true},
+		{"-1h", 0, false},
+		{"0s", 0, false},
+		{"500ms", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseLifetime(tt.value)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseLifetime(%q) = %v, %v", tt.value, got, err)
+		}
+	}
+}
+
+func TestDialWithoutSocket(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	if _, err := Dial(); err == nil {
+		t.Error("未设置 SSH_AUTH_SOCK 时应返回错误")
+	}
+}
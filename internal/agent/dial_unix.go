@@ -0,0 +1,23 @@
+//go:build !windows
+
+package agent
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+)
+
+// dialAgent 连接 SSH_AUTH_SOCK 指向的 unix socket
+func dialAgent() (io.ReadWriteCloser, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, fmt.Errorf("未设置 SSH_AUTH_SOCK，ssh-agent 可能没有运行")
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("无法连接 ssh-agent: %w", err)
+	}
+	return conn, nil
+}
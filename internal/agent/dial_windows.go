@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+// defaultPipe 是 Windows 自带的 OpenSSH Authentication Agent 服务使用的命名管道
+const defaultPipe = `\\.\pipe\openssh-ssh-agent`
+
+// dialAgent 连接 SSH_AUTH_SOCK 指向的命名管道或 unix socket，未设置时使用 OpenSSH 服务的默认管道
+func dialAgent() (io.ReadWriteCloser, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		socket = defaultPipe
+	}
+	if !strings.HasPrefix(socket, `\\.\pipe\`) {
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, fmt.Errorf("无法连接 ssh-agent: %w", err)
+		}
+		return conn, nil
+	}
+	pipe, err := os.OpenFile(socket, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("无法连接 ssh-agent（%s），请确认 OpenSSH Authentication Agent 服务已启动: %w", socket, err)
+	}
+	return pipe, nil
+}
@@ -0,0 +1,271 @@
+package allowedsigners
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/fileutil"
+	"golang.org/x/crypto/ssh"
+)
+
+// Entry 表示 allowed_signers 中的一行签名者记录
+type Entry struct {
+	Line          int
+	Principals    []string
+	CertAuthority bool
+	Namespaces    []string
+	ValidAfter    string
+	ValidBefore   string
+	// Other 保存无法识别的选项，写回时原样保留
+	Other       []string
+	Key         ssh.PublicKey
+	Comment     string
+	Fingerprint string
+}
+
+// String 按 allowed_signers 格式生成记录行
+func (e Entry) String() string {
+	var options []string
+	if e.CertAuthority {
+		options = append(options, "cert-authority")
+	}
+	if len(e.Namespaces) > 0 {
+		options = append(options, `namespaces="`+strings.Join(e.Namespaces, ",")+`"`)
+	}
+	if e.ValidAfter != "" {
+		options = append(options, `valid-after="`+e.ValidAfter+`"`)
+	}
+	if e.ValidBefore != "" {
+		options = append(options, `valid-before="`+e.ValidBefore+`"`)
+	}
+	options = append(options, e.Other...)
+
+	principals := strings.Join(e.Principals, ",")
+	if strings.ContainsAny(principals, " \t") {
+		principals = `"` + principals + `"`
+	}
+	line := principals
+	if len(options) > 0 {
+		line += " " + strings.Join(options, ",")
+	}
+	line += " " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(e.Key)))
+	if e.Comment != "" {
+		line += " " + e.Comment
+	}
+	return line
+}
+
+// Allows 判断记录是否允许指定的签名命名空间，未限制命名空间时允许所有
+func (e Entry) Allows(namespace string) bool {
+	if len(e.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range e.Namespaces {
+		if ns == namespace || ns == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPrincipal 判断记录中是否直接列出了指定的主体（不展开通配符）
+func (e Entry) HasPrincipal(principal string) bool {
+	for _, p := range e.Principals {
+		if p == principal {
+			return true
+		}
+	}
+	return false
+}
+
+// validTime 匹配 ssh-keygen 接受的 YYYYMMDD[HHMM[SS]][Z] 时间格式
+var validTime = regexp.MustCompile(`^\d{8}(\d{4}(\d{2})?)?Z?$`)
+
+// Validate 检查记录中的字段
+func (e Entry) Validate() error {
+	if len(e.Principals) == 0 {
+		return fmt.Errorf("请至少填写一个主体（通常为提交邮箱）")
+	}
+	for _, p := range e.Principals {
+		if p == "" || strings.ContainsAny(p, "\"") {
+			return fmt.Errorf("无效的主体: %q", p)
+		}
+	}
+	for _, ns := range e.Namespaces {
+		if ns == "" || strings.ContainsAny(ns, "\" \t") {
+			return fmt.Errorf("无效的命名空间: %q", ns)
+		}
+	}
+	for _, t := range []string{e.ValidAfter, e.ValidBefore} {
+		if t != "" && !validTime.MatchString(t) {
+			return fmt.Errorf("时间 %s 应为 YYYYMMDD[HHMM[SS]][Z] 格式", t)
+		}
+	}
+	if e.ValidAfter != "" && e.ValidBefore != "" && len(e.ValidAfter) == len(e.ValidBefore) && e.ValidAfter >= e.ValidBefore {
+		return fmt.Errorf("valid-after 应早于 valid-before")
+	}
+	if e.Key == nil {
+		return fmt.Errorf("缺少公钥")
+	}
+	return nil
+}
+
+// File 表示一个 allowed_signers 文件，保留注释和无法解析的行
+type File struct {
+	Path    string
+	lines   fileutil.Lines
+	Entries []Entry
+}
+
+// DefaultPath 返回 ~/.ssh/allowed_signers 路径
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".ssh", "allowed_signers"), nil
+}
+
+// Load 读取 allowed_signers 文件，文件不存在时返回空文件
+func Load(path string) (*File, error) {
+	lines, err := fileutil.ReadLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 allowed_signers: %w", err)
+	}
+	f := &File{Path: path, lines: lines}
+	f.reparse()
+	return f, nil
+}
+
+// ParseLine 解析单行记录：主体列表、可选的选项列表、公钥和注释；注释行和空行返回 false
+func ParseLine(line string) (Entry, bool, error) {
+	rest := strings.TrimSpace(line)
+	if rest == "" || strings.HasPrefix(rest, "#") {
+		return Entry{}, false, nil
+	}
+
+	var entry Entry
+	principals, rest := nextField(rest)
+	entry.Principals = strings.Split(strings.Trim(principals, `"`), ",")
+
+	// 第二个字段不是密钥类型时为选项列表
+	if field, after := nextField(rest); field != "" && !isKeyType(field) {
+		for _, option := range splitOptions(field) {
+			name, value, _ := strings.Cut(option, "=")
+			value = strings.Trim(value, `"`)
+			switch strings.ToLower(name) {
+			case "cert-authority":
+				entry.CertAuthority = true
+			case "namespaces":
+				entry.Namespaces = strings.Split(value, ",")
+			case "valid-after":
+				entry.ValidAfter = value
+			case "valid-before":
+				entry.ValidBefore = value
+			default:
+				entry.Other = append(entry.Other, option)
+			}
+		}
+		rest = after
+	}
+
+	key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(rest))
+	if err != nil {
+		return Entry{}, true, fmt.Errorf("无法解析公钥: %w", err)
+	}
+	entry.Key = key
+	entry.Comment = comment
+	entry.Fingerprint = ssh.FingerprintSHA256(key)
+	return entry, true, nil
+}
+
+// nextField 返回下一个以空白分隔的字段，引号内的空白不作为分隔
+func nextField(s string) (string, string) {
+	s = strings.TrimLeft(s, " \t")
+	inQuote := false
+	for i, c := range s {
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case (c == ' ' || c == '\t') && !inQuote:
+			return s[:i], strings.TrimLeft(s[i:], " \t")
+		}
+	}
+	return s, ""
+}
+
+// splitOptions 按逗号拆分选项，引号内的逗号不作为分隔
+func splitOptions(s string) []string {
+	var options []string
+	inQuote := false
+	start := 0
+	for i, c := range s {
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case c == ',' && !inQuote:
+			options = append(options, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(options, s[start:])
+}
+
+// isKeyType 判断字段是否为公钥类型
+func isKeyType(field string) bool {
+	for _, prefix := range []string{"ssh-", "ecdsa-", "sk-", "rsa-sha2-"} {
+		if strings.HasPrefix(field, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// reparse 去掉被删除的行并重新解析记录，无法解析的行保留但不出现在 Entries 中
+func (f *File) reparse() {
+	f.lines.Compact()
+	f.Entries = nil
+	for i, line := range f.lines {
+		if entry, ok, err := ParseLine(line); ok && err == nil {
+			entry.Line = i + 1
+			f.Entries = append(f.Entries, entry)
+		}
+	}
+}
+
+// Add 追加一条记录
+func (f *File) Add(entry Entry) {
+	f.lines = append(f.lines, entry.String())
+	f.reparse()
+}
+
+// Update 用新内容替换原有记录所在的行
+func (f *File) Update(old, entry Entry) {
+	f.lines[old.Line-1] = entry.String()
+	f.reparse()
+}
+
+// Remove 删除一条记录
+func (f *File) Remove(entry Entry) {
+	f.lines.Delete(entry.Line)
+	f.reparse()
+}
+
+// Find 返回包含指定主体且公钥相同的记录
+func (f *File) Find(principal string, key ssh.PublicKey) (Entry, bool) {
+	fingerprint := ssh.FingerprintSHA256(key)
+	for _, entry := range f.Entries {
+		if entry.Fingerprint == fingerprint && entry.HasPrincipal(principal) {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Save 写回文件，修改前的内容保存为 allowed_signers.old
+func (f *File) Save() error {
+	return fileutil.WriteAtomic(f.Path, f.lines.Bytes(), fileutil.Perm(f.Path, 0644), ".old")
+}
@@ -0,0 +1,39 @@
+package clipboard
+
+import (
+	"os"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// 复制方式
+const (
+	MethodNative = "系统剪贴板"
+	MethodOSC52  = "OSC52"
+)
+
+// IsRemoteSession 判断当前是否运行在 SSH 会话中
+func IsRemoteSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// Copy 将文本复制到剪贴板，SSH 会话中或本机剪贴板不可用时使用 OSC52 转义序列交给本地终端处理
+func Copy(text string) (string, error) {
+	if !IsRemoteSession() {
+		if err := clipboard.WriteAll(text); err == nil {
+			return MethodNative, nil
+		}
+	}
+
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if os.Getenv("STY") != "" {
+		seq = seq.Screen()
+	}
+	if _, err := seq.WriteTo(os.Stderr); err != nil {
+		return "", err
+	}
+	return MethodOSC52, nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import "strings"
+
+// DefaultPort SSH 的默认端口
+const DefaultPort = "22"
+
+// Target 返回条目实际连接的主机名和端口；未设置 HostName 时别名即主机名
+func (h SSHHost) Target() (string, string) {
+	hostname, port := h.HostName, h.Port
+	if hostname == "" {
+		hostname = h.Host
+	}
+	if port == "" {
+		port = DefaultPort
+	}
+	return hostname, port
+}
+
+// FindHost 按别名查找主机配置，通配符条目不参与查找
+func (c *SSHConfig) FindHost(alias string) (SSHHost, bool) {
+	for _, host := range c.hosts {
+		if !host.IsPattern() && host.Host == alias {
+			return host, true
+		}
+	}
+	return SSHHost{}, false
+}
+
+// MatchHosts 返回实际连接到指定主机、端口和用户的别名；user 或条目的 User 为空时不比较用户
+func (c *SSHConfig) MatchHosts(hostname, port, user string) []SSHHost {
+	if port == "" {
+		port = DefaultPort
+	}
+	var result []SSHHost
+	for _, host := range c.hosts {
+		if host.IsPattern() {
+			continue
+		}
+		h, p := host.Target()
+		if !strings.EqualFold(h, hostname) || p != port {
+			continue
+		}
+		if user != "" && host.User != "" && host.User != user {
+			continue
+		}
+		result = append(result, host)
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SSHDir 返回用户的 ~/.ssh 目录路径
+func SSHDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".ssh"), nil
+}
+
+// ExpandPath 将以 ~ 开头的路径展开为绝对路径
+func ExpandPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~\\") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
+// ContractPath 将位于用户主目录下的路径转换为 ~/ 开头的形式，并统一使用正斜杠
+func ContractPath(path string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(homeDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	if rel == "." {
+		return "~"
+	}
+	return "~/" + filepath.ToSlash(rel)
+}
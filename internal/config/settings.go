@@ -0,0 +1,150 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultSSHCommand 打开会话时默认执行的命令
+const DefaultSSHCommand = "ssh {host}"
+
+// SSHCommandEnv 可覆盖配置文件中会话命令模板的环境变量
+const SSHCommandEnv = "GIT_SSH_TUI_SSH_COMMAND"
+
+// Settings 表示程序自身的设置
+type Settings struct {
+	// SSHCommand 打开会话的命令模板，支持 {host}、{hostname}、{user}、{port} 占位符
+	SSHCommand string `json:"ssh_command,omitempty"`
+}
+
+// AppConfigDir 返回程序的配置目录
+func AppConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "git_ssh_tui"), nil
+}
+
+// SettingsPath 返回设置文件路径
+func SettingsPath() (string, error) {
+	dir, err := AppConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.json"), nil
+}
+
+// LoadSettings 读取设置文件，文件不存在时使用默认值；环境变量优先于配置文件
+func LoadSettings() (Settings, error) {
+	settings := Settings{SSHCommand: DefaultSSHCommand}
+	path, err := SettingsPath()
+	if err != nil {
+		return settings, err
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return settings, fmt.Errorf("无法读取设置: %w", err)
+	default:
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return settings, fmt.Errorf("无法解析设置 %s: %w", path, err)
+		}
+	}
+
+	if command := os.Getenv(SSHCommandEnv); command != "" {
+		settings.SSHCommand = command
+	}
+	if strings.TrimSpace(settings.SSHCommand) == "" {
+		settings.SSHCommand = DefaultSSHCommand
+	}
+	return settings, nil
+}
+
+// SSHCommandArgs 将会话命令模板拆分为参数并替换占位符。
+// 先按空白和引号拆分再替换，主机名中的特殊字符不会被再次解析。
+func (s Settings) SSHCommandArgs(host SSHHost) ([]string, error) {
+	args, err := SplitCommand(s.SSHCommand)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("会话命令为空")
+	}
+
+	// 未设置 HostName 和 Port 时按 ssh 的规则使用别名和 22 端口，避免替换出空参数
+	hostname, port := host.Target()
+	replacer := strings.NewReplacer(
+		"{host}", host.Host,
+		"{hostname}", hostname,
+		"{user}", host.User,
+		"{port}", port,
+	)
+	for i, arg := range args {
+		args[i] = replacer.Replace(arg)
+	}
+	return args, nil
+}
+
+// SplitCommand 按 shell 的规则拆分命令行，支持单引号、双引号和反斜杠转义。
+// Windows 上反斜杠是路径分隔符，只在引号前才作为转义
+func SplitCommand(command string) ([]string, error) {
+	return splitCommand(command, runtime.GOOS == "windows")
+}
+
+// splitCommand 拆分命令行，windows 为 true 时按 Windows 的规则处理反斜杠
+func splitCommand(command string, windows bool) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	runes := []rune(command)
+	for i, r := range runes {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'' && isEscape(runes[i+1:], windows):
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("会话命令中的引号不匹配: %s", command)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// isEscape 判断反斜杠是否作为转义，rest 为其后的内容；Windows 上只有紧跟引号时才是
+func isEscape(rest []rune, windows bool) bool {
+	return !windows || len(rest) > 0 && (rest[0] == '"' || rest[0] == '\'')
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		windows bool
+		want    []string
+	}{
+		{`ssh {host}`, false, []string{"ssh", "{host}"}},
+		{`tmux new-window -n {host} "ssh {host}"`, false, []string{"tmux", "new-window", "-n", "{host}", "ssh {host}"}},
+		{`echo a\ b 'c\d' "e\"f"`, false, []string{"echo", "a b", `c\d`, `e"f`}},
+		{`C:\Tools\putty.exe -ssh {hostname}`, false, []string{"C:Toolsputty.exe", "-ssh", "{hostname}"}},
+		{`C:\Tools\putty.exe -ssh {hostname}`, true, []string{`C:\Tools\putty.exe`, "-ssh", "{hostname}"}},
+		{`"C:\Program Files\PuTTY\putty.exe" -P {port}`, true, []string{`C:\Program Files\PuTTY\putty.exe`, "-P", "{port}"}},
+		{`cmd /c "echo \"a\"" C:\`, true, []string{"cmd", "/c", `echo "a"`, `C:\`}},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.command, tt.windows)
+		if err != nil {
+			t.Errorf("splitCommand(%s, %v) 出错: %v", tt.command, tt.windows, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%s, %v) = %q，期望 %q", tt.command, tt.windows, got, tt.want)
+		}
+	}
+
+	for _, command := range []string{`ssh "host`, `ssh host\`} {
+		if _, err := splitCommand(command, false); err == nil {
+			t.Errorf("splitCommand(%s) 应返回错误", command)
+		}
+	}
+}
+
+func TestSSHCommandArgs(t *testing.T) {
+	settings := Settings{SSHCommand: "putty -ssh {user}@{hostname} -P {port}"}
+	tests := []struct {
+		host SSHHost
+		want []string
+	}{
+		{SSHHost{Host: "work", HostName: "git.example.com", User: "git", Port: "2222"}, []string{"putty", "-ssh", "git@git.example.com", "-P", "2222"}},
+		// 未设置 HostName 和 Port 时使用别名和默认端口
+		{SSHHost{Host: "work", User: "git"}, []string{"putty", "-ssh", "git@work", "-P", "22"}},
+	}
+	for _, tt := range tests {
+		got, err := settings.SSHCommandArgs(tt.host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SSHCommandArgs(%s) = %q，期望 %q", tt.host.Host, got, tt.want)
+		}
+	}
+}
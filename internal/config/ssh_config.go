@@ -10,11 +10,17 @@ import (
 
 // SSHHost 表示一个 SSH 配置条目
 type SSHHost struct {
-	Host         string
-	HostName     string
-	User         string
-	Port         string
-	IdentityFile string
+	Host            string
+	HostName        string
+	User            string
+	Port            string
+	IdentityFile    string
+	CertificateFile string
+}
+
+// IsPattern 判断条目是否为通配符或多个模式（如 "Host *"、"Host a b"），这类条目不对应具体主机
+func (h SSHHost) IsPattern() bool {
+	return strings.ContainsAny(h.Host, "*?! \t")
 }
 
 // SSHConfig 管理 SSH 配置文件
@@ -106,6 +112,10 @@ func (c *SSHConfig) Load() error {
 			if currentHost != nil {
 				currentHost.IdentityFile = value
 			}
+		case "certificatefile":
+			if currentHost != nil {
+				currentHost.CertificateFile = value
+			}
 		}
 	}
 
@@ -138,6 +148,9 @@ func (c *SSHConfig) Save() error {
 		if host.IdentityFile != "" {
 			fmt.Fprintf(file, "    IdentityFile %s\n", host.IdentityFile)
 		}
+		if host.CertificateFile != "" {
+			fmt.Fprintf(file, "    CertificateFile %s\n", host.CertificateFile)
+		}
 		// 默认添加 IdentitiesOnly yes 设置
 		fmt.Fprintf(file, "    IdentitiesOnly yes\n")
 		fmt.Fprintln(file)
@@ -180,4 +193,4 @@ func ValidateIdentityFile(path string) (bool, string) {
 		return false, "这是一个 PuTTY 格式的密钥，标准的 OpenSSH 可能无法使用。请使用 puttygen.exe 将其转换为 OpenSSH 格式后再使用。"
 	}
 	return true, ""
-}
\ No newline at end of file
+}
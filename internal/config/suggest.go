@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WellKnownGitHosts 常见 Git 托管平台的 SSH 主机名
+var WellKnownGitHosts = []string{
+	"github.com",
+	"gitlab.com",
+	"bitbucket.org",
+	"ssh.dev.azure.com",
+}
+
+// HostNameSuggestions 返回已有配置中使用过的 HostName，以及常见 Git 托管平台的主机名
+func (c *SSHConfig) HostNameSuggestions() []string {
+	values := c.usedValues(func(h SSHHost) string { return h.HostName })
+	for _, name := range WellKnownGitHosts {
+		if !containsFold(values, name) {
+			values = append(values, name)
+		}
+	}
+	return values
+}
+
+// UserSuggestions 返回已有配置中使用过的 User
+func (c *SSHConfig) UserSuggestions() []string {
+	return c.usedValues(func(h SSHHost) string { return h.User })
+}
+
+// usedValues 收集所有主机块中某个字段的非空去重值，并按出现次数降序排列
+func (c *SSHConfig) usedValues(field func(SSHHost) string) []string {
+	counts := map[string]int{}
+	var values []string
+	for _, host := range c.hosts {
+		value := field(host)
+		if value == "" {
+			continue
+		}
+		if counts[value] == 0 {
+			values = append(values, value)
+		}
+		counts[value]++
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return counts[values[i]] > counts[values[j]]
+	})
+	return values
+}
+
+// CompletePath 根据已输入的路径前缀补全文件路径，返回值保持用户输入的形式（例如 ~/ 开头），目录以 / 结尾
+func CompletePath(input string, skip func(name string) bool) []string {
+	input = strings.ReplaceAll(input, "\\", "/")
+	dirPart, prefix := "", input
+	if i := strings.LastIndex(input, "/"); i >= 0 {
+		dirPart, prefix = input[:i+1], input[i+1:]
+	}
+
+	dir := ExpandPath(dirPart)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(filepath.FromSlash(dir))
+	if err != nil {
+		return nil
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			continue
+		}
+		// 未输入 . 时不补全隐藏文件
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+		if entry.IsDir() {
+			matches = append(matches, dirPart+name+"/")
+			continue
+		}
+		if skip != nil && skip(name) {
+			continue
+		}
+		matches = append(matches, dirPart+name)
+	}
+	return matches
+}
+
+// containsFold 判断切片中是否包含忽略大小写后相同的字符串
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HostTemplate 表示一个 Git 托管平台的主机模板，用于预填添加表单和账户向导。
+// HostName 为空表示自建服务器，需要用户填写。
+type HostTemplate struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Alias       string `json:"alias,omitempty"`
+	HostName    string `json:"hostname,omitempty"`
+	Port        string `json:"port,omitempty"`
+	User        string `json:"user,omitempty"`
+	KeyType     string `json:"key_type,omitempty"`
+	KeysURL     string `json:"keys_url,omitempty"`
+}
+
+// SelfHosted 判断模板是否需要填写服务器地址
+func (t HostTemplate) SelfHosted() bool {
+	return t.HostName == ""
+}
+
+// Address 返回模板的主机名和端口，用于列表显示
+func (t HostTemplate) Address() string {
+	hostname := t.HostName
+	if hostname == "" {
+		hostname = "<服务器>"
+	}
+	if t.Port != "" && t.Port != DefaultPort {
+		return hostname + ":" + t.Port
+	}
+	return hostname
+}
+
+// KeysPage 返回上传公钥的页面地址，{hostname} 替换为实际的主机名
+func (t HostTemplate) KeysPage(hostname string) string {
+	return strings.ReplaceAll(t.KeysURL, "{hostname}", hostname)
+}
+
+// BuiltinTemplates 内置的平台模板
+var BuiltinTemplates = []HostTemplate{
+	{Name: "GitHub", Alias: "github", HostName: "github.com", User: "git", KeysURL: "https://github.com/settings/ssh/new"},
+	{Name: "GitHub-443", Description: "端口 22 被防火墙拦截时改用 443", Alias: "github", HostName: "ssh.github.com", Port: "443", User: "git", KeysURL: "https://github.com/settings/ssh/new"},
+	{Name: "GitLab", Alias: "gitlab", HostName: "gitlab.com", User: "git", KeysURL: "https://gitlab.com/-/user_settings/ssh_keys"},
+	{Name: "Bitbucket", Alias: "bitbucket", HostName: "bitbucket.org", User: "git", KeysURL: "https://bitbucket.org/account/settings/ssh-keys/"},
+	// Azure DevOps 长期只接受 RSA 密钥；公钥页面在组织之下，{organization} 需要用户自行替换
+	{Name: "Azure DevOps", Description: "只接受 RSA 密钥", Alias: "azure", HostName: "ssh.dev.azure.com", User: "git", KeyType: "rsa", KeysURL: "https://dev.azure.com/{organization}/_usersSettings/keys"},
+	{Name: "Gitea", Description: "自建 Gitea/Forgejo", Alias: "gitea", User: "git", KeysURL: "https://{hostname}/user/settings/keys"},
+	{Name: "Gerrit", Description: "User 为 Gerrit 用户名", Alias: "gerrit", Port: "29418", KeysURL: "https://{hostname}/settings/#SSHKeys"},
+}
+
+// templateFile 是用户自定义模板文件的格式
+type templateFile struct {
+	Templates []HostTemplate `json:"templates"`
+}
+
+// TemplatesPath 返回用户自定义模板文件的路径，其中的条目会覆盖同名的内置模板
+func TemplatesPath() (string, error) {
+	dir, err := AppConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "host_templates.json"), nil
+}
+
+// LoadTemplates 返回内置模板，并合并用户自定义模板（存在时）
+func LoadTemplates() ([]HostTemplate, error) {
+	templates := append([]HostTemplate{}, BuiltinTemplates...)
+
+	path, err := TemplatesPath()
+	if err != nil {
+		return templates, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return templates, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("无法读取模板文件: %w", err)
+	}
+	var user templateFile
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, fmt.Errorf("无法解析模板文件 %s: %w", path, err)
+	}
+
+	for _, template := range user.Templates {
+		if template.Name == "" {
+			return nil, fmt.Errorf("模板文件 %s 中有未命名的模板", path)
+		}
+		replaced := false
+		for i := range templates {
+			if strings.EqualFold(templates[i].Name, template.Name) {
+				templates[i] = template
+				replaced = true
+			}
+		}
+		if !replaced {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
@@ -0,0 +1,121 @@
+package fileutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteAtomic 先写入同目录下的临时文件再重命名，写入中断时原文件保持完整。
+// backupSuffix 不为空且原文件存在时，先把原内容复制为 path+backupSuffix；备份只有所有者可读写
+func WriteAtomic(path string, data []byte, perm os.FileMode, backupSuffix string) error {
+	name := filepath.Base(path)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("无法创建目录: %w", err)
+	}
+	if backupSuffix != "" {
+		if err := backup(path, path+backupSuffix); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(dir, name+".tmp*")
+	if err != nil {
+		return fmt.Errorf("无法创建临时文件: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("无法写入 %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("无法写入 %s: %w", name, err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return fmt.Errorf("无法设置文件权限: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("无法替换 %s: %w", name, err)
+	}
+	return nil
+}
+
+// backup 复制原文件，原文件不存在时跳过。备份可能含有私钥，已存在的备份也会收紧为 0600
+func backup(path, backupPath string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("无法读取 %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		return fmt.Errorf("无法备份 %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Chmod(backupPath, 0600); err != nil {
+		return fmt.Errorf("无法设置文件权限: %w", err)
+	}
+	return nil
+}
+
+// Perm 返回已有文件的权限，文件不存在时返回 fallback；改写用户的配置文件时用它保留原有权限
+func Perm(path string, fallback os.FileMode) os.FileMode {
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().Perm()
+	}
+	return fallback
+}
+
+// Lines 按行保存文本文件的原始内容，修改时只改动个别行，注释和无法识别的行原样写回
+type Lines []string
+
+// deleted 标记待删除的行，Compact 之前其余行的行号保持不变
+const deleted = "\x00"
+
+// ReadLines 按行读取文件，文件不存在时返回空内容
+func ReadLines(path string) (Lines, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lines Lines
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// Delete 标记删除第 n 行（从 1 开始）
+func (l Lines) Delete(n int) {
+	l[n-1] = deleted
+}
+
+// Compact 移除标记删除的行
+func (l *Lines) Compact() {
+	kept := (*l)[:0]
+	for _, line := range *l {
+		if line != deleted {
+			kept = append(kept, line)
+		}
+	}
+	*l = kept
+}
+
+// Bytes 返回每行以换行结尾的文件内容
+func (l Lines) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, line := range l {
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
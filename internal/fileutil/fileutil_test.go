@@ -0,0 +1,94 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "config")
+
+	// 原文件不存在时不生成备份，并创建所需的目录
+	if err := WriteAtomic(path, []byte("one\n"), 0644, ".bak"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("不应生成备份: %v", err)
+	}
+
+	if err := os.Chmod(path, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAtomic(path, []byte("two\n"), Perm(path, 0644), ".bak"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "two\n" {
+		t.Errorf("内容 = %q", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0640 {
+		t.Errorf("权限 = %v，期望保留 0640", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path + ".bak"); string(data) != "one\n" {
+		t.Errorf("备份内容 = %q", data)
+	}
+
+	// 已存在的备份同样收紧为 0600
+	if err := os.Chmod(path+".bak", 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAtomic(path, []byte("three\n"), 0600, ".bak"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(path + ".bak"); info.Mode().Perm() != 0600 {
+		t.Errorf("备份权限 = %v", info.Mode().Perm())
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("权限 = %v", info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("目录中留下了临时文件: %v", entries)
+	}
+}
+
+func TestPerm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if perm := Perm(path, 0644); perm != 0644 {
+		t.Errorf("不存在的文件 = %v", perm)
+	}
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if perm := Perm(path, 0644); perm != 0600 {
+		t.Errorf("已有文件 = %v", perm)
+	}
+}
+
+func TestLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines")
+	lines, err := ReadLines(path)
+	if err != nil || len(lines) != 0 {
+		t.Fatalf("不存在的文件 = %v, %v", lines, err)
+	}
+
+	if err := os.WriteFile(path, []byte("a\n\n# c\nb"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if lines, err = ReadLines(path); err != nil {
+		t.Fatal(err)
+	}
+	// 删除多行时行号在 Compact 之前保持不变
+	lines.Delete(1)
+	lines.Delete(4)
+	lines = append(lines, "d")
+	lines.Compact()
+	if got := string(lines.Bytes()); got != "\n# c\nd\n" {
+		t.Errorf("内容 = %q", got)
+	}
+}
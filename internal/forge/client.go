@@ -0,0 +1,209 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Kind 表示 Git 托管平台的 API 类型
+type Kind int
+
+const (
+	GitHub Kind = iota
+	GitLab
+	Gitea
+)
+
+// Kinds 是支持的平台，按界面中切换的顺序排列
+var Kinds = []Kind{GitHub, GitLab, Gitea}
+
+func (k Kind) String() string {
+	switch k {
+	case GitHub:
+		return "GitHub"
+	case GitLab:
+		return "GitLab"
+	case Gitea:
+		return "Gitea/Forgejo"
+	}
+	return "未知平台"
+}
+
+// TokenEnv 返回该平台常用的令牌环境变量
+func (k Kind) TokenEnv() []string {
+	switch k {
+	case GitHub:
+		return []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	case GitLab:
+		return []string{"GITLAB_TOKEN"}
+	case Gitea:
+		return []string{"GITEA_TOKEN", "FORGEJO_TOKEN"}
+	}
+	return nil
+}
+
+// apiHost 去掉 SSH 专用的子域名，如 ssh.github.com、altssh.gitlab.com
+func apiHost(hostname string) string {
+	hostname = strings.ToLower(hostname)
+	for _, prefix := range []string{"ssh.", "altssh."} {
+		if rest := strings.TrimPrefix(hostname, prefix); rest != hostname && strings.Contains(rest, ".") {
+			return rest
+		}
+	}
+	return hostname
+}
+
+// DetectKind 根据 SSH 主机名猜测平台类型
+func DetectKind(hostname string) (Kind, bool) {
+	host := apiHost(hostname)
+	switch {
+	case host == "github.com" || strings.Contains(host, "github"):
+		return GitHub, true
+	case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+		return GitLab, true
+	case host == "codeberg.org" || strings.Contains(host, "gitea") || strings.Contains(host, "forgejo"):
+		return Gitea, true
+	}
+	return GitHub, false
+}
+
+// DefaultBaseURL 返回平台在指定主机上的 API 地址；github.com 使用 api.github.com，其他主机视为自建实例
+func DefaultBaseURL(kind Kind, hostname string) string {
+	host := apiHost(hostname)
+	switch kind {
+	case GitHub:
+		if host == "" || host == "github.com" {
+			return "https://api.github.com"
+		}
+		return "https://" + host + "/api/v3"
+	case GitLab:
+		if host == "" {
+			host = "gitlab.com"
+		}
+		return "https://" + host + "/api/v4"
+	case Gitea:
+		if host == "" {
+			host = "codeberg.org"
+		}
+		return "https://" + host + "/api/v1"
+	}
+	return ""
+}
+
+// Client 是 Git 平台的 REST API 客户端，BaseURL 可指向自建实例或本地测试服务
+type Client struct {
+	Kind    Kind
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewClient 创建 API 客户端
+func NewClient(kind Kind, baseURL, token string) *Client {
+	return &Client{
+		Kind:    kind,
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// APIError 表示平台返回的错误响应
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	reason := ""
+	switch e.Status {
+	case http.StatusUnauthorized:
+		reason = "，令牌无效或已过期"
+	case http.StatusForbidden:
+		reason = "，令牌缺少所需权限"
+	case http.StatusNotFound:
+		reason = "，仓库不存在或令牌无权访问"
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("HTTP %d%s", e.Status, reason)
+	}
+	return fmt.Sprintf("HTTP %d: %s%s", e.Status, e.Message, reason)
+}
+
+// do 发送请求并解析 JSON 响应，body 和 out 可以为 nil
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch c.Kind {
+	case GitHub:
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	case GitLab:
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	case Gitea:
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{Status: resp.StatusCode, Message: errorMessage(data)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("无法解析 %s 的响应: %w", c.Kind, err)
+	}
+	return nil
+}
+
+// errorMessage 提取错误响应中的说明；GitLab 的 message 可能是按字段分组的对象
+func errorMessage(data []byte) string {
+	var body struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return strings.TrimSpace(string(data))
+	}
+	switch message := body.Message.(type) {
+	case string:
+		return message
+	case map[string]any:
+		var parts []string
+		for field, value := range message {
+			parts = append(parts, fmt.Sprintf("%s %v", field, value))
+		}
+		return strings.Join(parts, "; ")
+	}
+	return body.Error
+}
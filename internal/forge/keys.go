@@ -0,0 +1,122 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// maxPages 列出密钥时最多读取的页数
+const maxPages = 20
+
+// RemoteKey 表示平台上的一个 SSH 公钥，Repo 不为空时为该仓库的部署密钥
+type RemoteKey struct {
+	ID          int64
+	Title       string
+	Key         string
+	Fingerprint string
+	Repo        string
+	ReadOnly    bool
+}
+
+// remoteKey 是各平台密钥接口共同的 JSON 字段；GitLab 部署密钥使用 can_push 表示可写
+type remoteKey struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Key      string `json:"key"`
+	ReadOnly *bool  `json:"read_only"`
+	CanPush  *bool  `json:"can_push"`
+}
+
+// toRemoteKey 转换为 RemoteKey 并计算指纹
+func (k remoteKey) toRemoteKey(repo string) RemoteKey {
+	key := RemoteKey{ID: k.ID, Title: k.Title, Key: k.Key, Repo: repo}
+	if pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k.Key)); err == nil {
+		key.Fingerprint = ssh.FingerprintSHA256(pub)
+	}
+	switch {
+	case k.ReadOnly != nil:
+		key.ReadOnly = *k.ReadOnly
+	case k.CanPush != nil:
+		key.ReadOnly = !*k.CanPush
+	}
+	return key
+}
+
+// keysPath 返回账户密钥（repo 为空）或仓库部署密钥的接口路径
+func (c *Client) keysPath(repo string) (string, error) {
+	if repo == "" {
+		return "/user/keys", nil
+	}
+	repo = strings.Trim(repo, "/")
+	if !strings.Contains(repo, "/") {
+		return "", fmt.Errorf("仓库应写成 owner/repo 的形式")
+	}
+	if c.Kind == GitLab {
+		return "/projects/" + url.PathEscape(repo) + "/deploy_keys", nil
+	}
+	return "/repos/" + repo + "/keys", nil
+}
+
+// ListKeys 列出账户的 SSH 公钥，repo 不为空时列出该仓库的部署密钥
+func (c *Client) ListKeys(ctx context.Context, repo string) ([]RemoteKey, error) {
+	path, err := c.keysPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	pageSize, sizeParam := 100, "per_page"
+	if c.Kind == Gitea {
+		pageSize, sizeParam = 50, "limit"
+	}
+
+	var result []RemoteKey
+	for page := 1; page <= maxPages; page++ {
+		var keys []remoteKey
+		query := fmt.Sprintf("?%s=%d&page=%d", sizeParam, pageSize, page)
+		if err := c.do(ctx, http.MethodGet, path+query, nil, &keys); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			result = append(result, key.toRemoteKey(repo))
+		}
+		if len(keys) < pageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// AddKey 上传公钥到账户，repo 不为空时添加为该仓库的部署密钥（readOnly 控制能否推送）
+func (c *Client) AddKey(ctx context.Context, repo, title, key string, readOnly bool) (RemoteKey, error) {
+	path, err := c.keysPath(repo)
+	if err != nil {
+		return RemoteKey{}, err
+	}
+	body := map[string]any{"title": title, "key": strings.TrimSpace(key)}
+	if repo != "" {
+		if c.Kind == GitLab {
+			body["can_push"] = !readOnly
+		} else {
+			body["read_only"] = readOnly
+		}
+	}
+	var created remoteKey
+	if err := c.do(ctx, http.MethodPost, path, body, &created); err != nil {
+		return RemoteKey{}, err
+	}
+	return created.toRemoteKey(repo), nil
+}
+
+// FindKey 按指纹查找密钥
+func FindKey(keys []RemoteKey, fingerprint string) (RemoteKey, bool) {
+	for _, key := range keys {
+		if key.Fingerprint == fingerprint {
+			return key, true
+		}
+	}
+	return RemoteKey{}, false
+}
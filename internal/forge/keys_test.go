@@ -0,0 +1,269 @@
+package forge
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// fakeForge 模拟平台的密钥接口，记录收到的请求
+type fakeForge struct {
+	t     *testing.T
+	kind  Kind
+	key   string
+	total int // 列出时返回的密钥数量，小于 0 表示每页都返回满页
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []map[string]any
+}
+
+func newFakeForge(t *testing.T, kind Kind, total int) (*fakeForge, *Client) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ssh.NewPublicKey(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeForge{t: t, kind: kind, key: strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))), total: total}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, NewClient(kind, server.URL+"/", "secret")
+}
+
+func (f *fakeForge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	f.bodies = append(f.bodies, body)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+		sizeParam := "per_page"
+		if f.kind == Gitea {
+			sizeParam = "limit"
+		}
+		size, _ := strconv.Atoi(r.URL.Query().Get(sizeParam))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		count := size
+		if f.total >= 0 {
+			count = min(size, max(f.total-(page-1)*size, 0))
+		}
+		keys := make([]map[string]any, count)
+		for i := range keys {
+			keys[i] = map[string]any{"id": (page-1)*size + i + 1, "title": "key", "key": f.key}
+		}
+		json.NewEncoder(w).Encode(keys)
+	case http.MethodPost:
+		body["id"] = 42
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func (f *fakeForge) last() (*http.Request, map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[len(f.requests)-1], f.bodies[len(f.bodies)-1]
+}
+
+func TestAuthHeaders(t *testing.T) {
+	tests := []struct {
+		kind   Kind
+		header string
+		want   string
+	}{
+		{GitHub, "Authorization", "Bearer secret"},
+		{GitLab, "PRIVATE-TOKEN", "secret"},
+		{Gitea, "Authorization", "token secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			f, client := newFakeForge(t, tt.kind, 0)
+			if _, err := client.ListKeys(context.Background(), ""); err != nil {
+				t.Fatal(err)
+			}
+			req, _ := f.last()
+			if got := req.Header.Get(tt.header); got != tt.want {
+				t.Errorf("%s = %q，期望 %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysPath(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		repo string
+		want string
+	}{
+		{GitHub, "", "/user/keys"},
+		{GitLab, "", "/user/keys"},
+		{Gitea, "", "/user/keys"},
+		{GitHub, "o/r", "/repos/o/r/keys"},
+		{Gitea, "/o/r/", "/repos/o/r/keys"},
+		{GitLab, "o/r", "/projects/o%2Fr/deploy_keys"},
+	}
+	for _, tt := range tests {
+		f, client := newFakeForge(t, tt.kind, 0)
+		if _, err := client.ListKeys(context.Background(), tt.repo); err != nil {
+			t.Fatal(err)
+		}
+		req, _ := f.last()
+		if got := req.URL.EscapedPath(); got != tt.want {
+			t.Errorf("%s %q 的路径 = %s，期望 %s", tt.kind, tt.repo, got, tt.want)
+		}
+	}
+
+	client := NewClient(GitHub, "http://127.0.0.1", "")
+	if _, err := client.ListKeys(context.Background(), "repo"); err == nil {
+		t.Error("缺少 owner 的仓库应返回错误")
+	}
+}
+
+func TestAddKeyAccess(t *testing.T) {
+	tests := []struct {
+		kind     Kind
+		repo     string
+		readOnly bool
+		field    string
+		want     any
+	}{
+		{GitHub, "o/r", true, "read_only", true},
+		{Gitea, "o/r", false, "read_only", false},
+		{GitLab, "o/r", true, "can_push", false},
+		{GitLab, "o/r", false, "can_push", true},
+	}
+	for _, tt := range tests {
+		f, client := newFakeForge(t, tt.kind, 0)
+		created, err := client.AddKey(context.Background(), tt.repo, "laptop", f.key+"\n", tt.readOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, body := f.last()
+		if req.Method != http.MethodPost || body["title"] != "laptop" || body["key"] != f.key {
+			t.Errorf("%s 请求 = %s %+v", tt.kind, req.Method, body)
+		}
+		if got, ok := body[tt.field]; !ok || got != tt.want {
+			t.Errorf("%s 的 %s = %v，期望 %v", tt.kind, tt.field, got, tt.want)
+		}
+		if created.ID != 42 || created.Repo != tt.repo || created.ReadOnly != tt.readOnly || created.Fingerprint == "" {
+			t.Errorf("%s 返回 %+v", tt.kind, created)
+		}
+	}
+
+	// 账户密钥不带访问权限字段
+	f, client := newFakeForge(t, GitLab, 0)
+	if _, err := client.AddKey(context.Background(), "", "laptop", f.key, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, body := f.last(); body["can_push"] != nil || body["read_only"] != nil {
+		t.Errorf("账户密钥请求 = %+v", body)
+	}
+}
+
+func TestListKeysPagination(t *testing.T) {
+	tests := []struct {
+		kind     Kind
+		total    int
+		keys     int
+		requests int
+	}{
+		{GitHub, 150, 150, 2},
+		{GitHub, 100, 100, 2},
+		{Gitea, 120, 120, 3},
+		{GitLab, 0, 0, 1},
+		// 服务器总是返回满页时在 maxPages 处停止
+		{GitHub, -1, maxPages * 100, maxPages},
+	}
+	for _, tt := range tests {
+		f, client := newFakeForge(t, tt.kind, tt.total)
+		keys, err := client.ListKeys(context.Background(), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != tt.keys || len(f.requests) != tt.requests {
+			t.Errorf("%s 共 %d 个密钥: 返回 %d 个，请求 %d 次，期望 %d 个、%d 次", tt.kind, tt.total, len(keys), len(f.requests), tt.keys, tt.requests)
+		}
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   string
+	}{
+		{http.StatusUnauthorized, `{"message":"Bad credentials"}`, "HTTP 401: Bad credentials，令牌无效或已过期"},
+		{http.StatusUnprocessableEntity, `{"message":{"key":["has already been taken"]}}`, "HTTP 422: key [has already been taken]"},
+		{http.StatusForbidden, `{"error":"insufficient_scope"}`, "HTTP 403: insufficient_scope，令牌缺少所需权限"},
+		{http.StatusNotFound, `not found`, "HTTP 404: not found，仓库不存在或令牌无权访问"},
+		{http.StatusInternalServerError, ``, "HTTP 500"},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		_, err := NewClient(GitLab, server.URL, "").ListKeys(context.Background(), "o/r")
+		server.Close()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Status != tt.status {
+			t.Fatalf("错误 = %v，期望 HTTP %d", err, tt.status)
+		}
+		if err.Error() != tt.want {
+			t.Errorf("错误 = %q，期望 %q", err.Error(), tt.want)
+		}
+	}
+}
+
+func TestErrorMessageFields(t *testing.T) {
+	got := errorMessage([]byte(`{"message":{"key":["is invalid"],"title":["can't be blank"]}}`))
+	if !strings.Contains(got, "key [is invalid]") || !strings.Contains(got, "title [can't be blank]") {
+		t.Errorf("errorMessage = %q", got)
+	}
+}
+
+func TestDetectKind(t *testing.T) {
+	tests := []struct {
+		host string
+		kind Kind
+		ok   bool
+		base string
+	}{
+		{"github.com", GitHub, true, "https://api.github.com"},
+		{"ssh.github.com", GitHub, true, "https://api.github.com"},
+		{"github.example.com", GitHub, true, "https://github.example.com/api/v3"},
+		{"altssh.gitlab.com", GitLab, true, "https://gitlab.com/api/v4"},
+		{"codeberg.org", Gitea, true, "https://codeberg.org/api/v1"},
+		{"git.example.com", GitHub, false, "https://git.example.com/api/v3"},
+	}
+	for _, tt := range tests {
+		kind, ok := DetectKind(tt.host)
+		if kind != tt.kind || ok != tt.ok {
+			t.Errorf("DetectKind(%q) = %s, %v", tt.host, kind, ok)
+		}
+		if base := DefaultBaseURL(kind, tt.host); base != tt.base {
+			t.Errorf("DefaultBaseURL(%s, %q) = %s", kind, tt.host, base)
+		}
+	}
+}
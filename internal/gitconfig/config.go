@@ -0,0 +1,369 @@
+package gitconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/fileutil"
+)
+
+// Entry 表示 git 配置文件中的一个键值
+type Entry struct {
+	Line       int
+	Section    string
+	Subsection string
+	Key        string
+	Value      string
+}
+
+// File 表示一个 git 配置文件（.git/config、~/.gitconfig 等），保留原始行以便只改动需要修改的部分
+type File struct {
+	Path    string
+	lines   fileutil.Lines
+	Entries []Entry
+	headers []header
+}
+
+// header 记录节标题所在的行，text 为该行中节标题本身（不含其后的键值）
+type header struct {
+	line       int
+	section    string
+	subsection string
+	text       string
+}
+
+// Load 读取配置文件，文件不存在时返回空文件
+func Load(path string) (*File, error) {
+	lines, err := fileutil.ReadLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 %s: %w", path, err)
+	}
+	f := &File{Path: path, lines: lines}
+	f.reparse()
+	return f, nil
+}
+
+// reparse 去掉被删除的行并重新解析所有节和键值
+func (f *File) reparse() {
+	f.lines.Compact()
+	f.Entries = nil
+	f.headers = nil
+
+	section, subsection := "", ""
+	for i, line := range f.lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			var rest string
+			section, subsection, rest = parseHeader(trimmed)
+			text := strings.TrimRight(line[:strings.Index(line, trimmed)+len(trimmed)-len(rest)], " \t")
+			f.headers = append(f.headers, header{line: i + 1, section: section, subsection: subsection, text: text})
+			// 允许在节标题后直接写键值，如 [core] bare = false
+			trimmed = strings.TrimSpace(rest)
+		}
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == ';' || section == "" {
+			continue
+		}
+		key, value := parseKeyValue(trimmed)
+		f.Entries = append(f.Entries, Entry{Line: i + 1, Section: section, Subsection: subsection, Key: key, Value: value})
+	}
+}
+
+// inlineHeader 返回与键值写在同一行的节标题（如 [core] bare = false 中的 [core]）
+func (f *File) inlineHeader(entry Entry) (string, bool) {
+	for _, h := range f.headers {
+		if h.line == entry.Line {
+			return h.text, true
+		}
+	}
+	return "", false
+}
+
+// setLine 改写键值所在的行，键值写在节标题之后时保留标题
+func (f *File) setLine(entry Entry, content string) {
+	if text, ok := f.inlineHeader(entry); ok {
+		content = text + " " + strings.TrimSpace(content)
+	}
+	f.lines[entry.Line-1] = content
+}
+
+// deleteLine 删除键值所在的行，键值写在节标题之后时只去掉键值
+func (f *File) deleteLine(entry Entry) {
+	if text, ok := f.inlineHeader(entry); ok {
+		f.lines[entry.Line-1] = text
+		return
+	}
+	f.lines.Delete(entry.Line)
+}
+
+// parseHeader 解析 [section "subsection"] 和旧式的 [section.subsection]，返回标题之后剩余的内容
+func parseHeader(line string) (string, string, string) {
+	end := strings.Index(line, "]")
+	if end < 0 {
+		return "", "", ""
+	}
+	// 子节名中可能包含转义的引号和 ]
+	if quote := strings.Index(line, "\""); quote >= 0 && quote < end {
+		section := strings.ToLower(strings.TrimSpace(line[1:quote]))
+		var sub strings.Builder
+		i := quote + 1
+		for ; i < len(line); i++ {
+			c := line[i]
+			if c == '\\' && i+1 < len(line) {
+				i++
+				sub.WriteByte(line[i])
+				continue
+			}
+			if c == '"' {
+				break
+			}
+			sub.WriteByte(c)
+		}
+		rest := ""
+		if close := strings.Index(line[i:], "]"); close >= 0 {
+			rest = line[i+close+1:]
+		}
+		return section, sub.String(), rest
+	}
+
+	name := strings.TrimSpace(line[1:end])
+	section, subsection, _ := strings.Cut(name, ".")
+	return strings.ToLower(section), strings.ToLower(subsection), line[end+1:]
+}
+
+// parseKeyValue 解析 key = value，处理引号、转义和行尾注释；只有键名时值为 "true"
+func parseKeyValue(line string) (string, string) {
+	name, raw, ok := strings.Cut(line, "=")
+	key := strings.ToLower(strings.TrimSpace(name))
+	if !ok {
+		return strings.Fields(key + " ")[0], "true"
+	}
+
+	var value strings.Builder
+	inQuote := false
+	pendingSpace := ""
+	raw = strings.TrimSpace(raw)
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '\\' && i+1 < len(raw):
+			i++
+			value.WriteString(pendingSpace)
+			pendingSpace = ""
+			switch raw[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			default:
+				value.WriteByte(raw[i])
+			}
+		case c == '"':
+			inQuote = !inQuote
+		case (c == '#' || c == ';') && !inQuote:
+			return key, value.String()
+		case (c == ' ' || c == '\t') && !inQuote:
+			// 引号外的连续空白折叠为一个，末尾空白丢弃
+			if value.Len() > 0 {
+				pendingSpace = " "
+			}
+		default:
+			value.WriteString(pendingSpace)
+			pendingSpace = ""
+			value.WriteByte(c)
+		}
+	}
+	return key, value.String()
+}
+
+// formatValue 在需要时为值加上引号并转义
+func formatValue(value string) string {
+	needQuote := value != strings.TrimSpace(value) || strings.ContainsAny(value, "#;")
+	var b strings.Builder
+	for _, c := range value {
+		switch c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	if needQuote {
+		return `"` + b.String() + `"`
+	}
+	return b.String()
+}
+
+// formatHeader 生成节标题
+func formatHeader(section, subsection string) string {
+	if subsection == "" {
+		return "[" + section + "]"
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(subsection)
+	return "[" + section + ` "` + escaped + `"]`
+}
+
+// matches 判断键值是否属于指定的节和键；节名和键名不区分大小写，子节名区分
+func (e Entry) matches(section, subsection, key string) bool {
+	return e.Section == strings.ToLower(section) && e.Subsection == subsection && e.Key == strings.ToLower(key)
+}
+
+// Get 返回键的值，多次出现时以最后一个为准
+func (f *File) Get(section, subsection, key string) (string, bool) {
+	values := f.GetAll(section, subsection, key)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// GetAll 返回键的所有值
+func (f *File) GetAll(section, subsection, key string) []string {
+	var values []string
+	for _, entry := range f.Entries {
+		if entry.matches(section, subsection, key) {
+			values = append(values, entry.Value)
+		}
+	}
+	return values
+}
+
+// Subsections 返回某个节下的所有子节名，按出现顺序去重
+func (f *File) Subsections(section string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, h := range f.headers {
+		if h.section == strings.ToLower(section) && h.subsection != "" && !seen[h.subsection] {
+			seen[h.subsection] = true
+			result = append(result, h.subsection)
+		}
+	}
+	return result
+}
+
+// Set 设置键的值：已存在时修改最后一处，否则追加到该节末尾，节不存在时新建
+func (f *File) Set(section, subsection, key, value string) {
+	for i := len(f.Entries) - 1; i >= 0; i-- {
+		entry := f.Entries[i]
+		if entry.matches(section, subsection, key) {
+			f.setLine(entry, "\t"+key+" = "+formatValue(value))
+			f.reparse()
+			return
+		}
+	}
+	f.Add(section, subsection, key, value)
+}
+
+// Add 在节末尾追加一个键值（用于 insteadOf 等可多次出现的键），节不存在时新建
+func (f *File) Add(section, subsection, key, value string) {
+	line := "\t" + key + " = " + formatValue(value)
+	if end := f.sectionEnd(section, subsection); end > 0 {
+		f.lines = append(f.lines[:end], append([]string{line}, f.lines[end:]...)...)
+	} else {
+		f.lines = append(f.lines, formatHeader(section, subsection), line)
+	}
+	f.reparse()
+}
+
+// ReplaceValue 将某个键中等于 oldValue 的值改为 newValue，返回修改的数量
+func (f *File) ReplaceValue(section, subsection, key, oldValue, newValue string) int {
+	changed := 0
+	for _, entry := range f.Entries {
+		if entry.matches(section, subsection, key) && entry.Value == oldValue {
+			f.setLine(entry, "\t"+key+" = "+formatValue(newValue))
+			changed++
+		}
+	}
+	f.reparse()
+	return changed
+}
+
+// Unset 删除键的所有值，返回删除的数量
+func (f *File) Unset(section, subsection, key string) int {
+	removed := 0
+	for _, entry := range f.Entries {
+		if entry.matches(section, subsection, key) {
+			f.deleteLine(entry)
+			removed++
+		}
+	}
+	f.reparse()
+	return removed
+}
+
+// UnsetValue 删除键中等于 value 的值，节因此变空时一并删除节标题，返回删除的数量
+func (f *File) UnsetValue(section, subsection, key, value string) int {
+	removed := 0
+	for _, entry := range f.Entries {
+		if entry.matches(section, subsection, key) && entry.Value == value {
+			f.deleteLine(entry)
+			removed++
+		}
+	}
+	f.reparse()
+	if removed > 0 && len(f.SectionEntries(section, subsection)) == 0 {
+		f.RemoveSection(section, subsection)
+	}
+	return removed
+}
+
+// SectionEntries 返回某个节中的所有键值
+func (f *File) SectionEntries(section, subsection string) []Entry {
+	var result []Entry
+	for _, entry := range f.Entries {
+		if entry.Section == strings.ToLower(section) && entry.Subsection == subsection {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// RemoveSection 删除节标题及其中的所有内容（同名节出现多次时全部删除）
+func (f *File) RemoveSection(section, subsection string) {
+	section = strings.ToLower(section)
+	for i, h := range f.headers {
+		if h.section != section || h.subsection != subsection {
+			continue
+		}
+		end := len(f.lines)
+		if i+1 < len(f.headers) {
+			end = f.headers[i+1].line - 1
+		}
+		for line := h.line; line <= end; line++ {
+			f.lines.Delete(line)
+		}
+	}
+	f.reparse()
+}
+
+// sectionEnd 返回指定节最后一次出现时最后一个非空行之后的位置（0 表示节不存在）
+func (f *File) sectionEnd(section, subsection string) int {
+	section = strings.ToLower(section)
+	end := 0
+	for i, h := range f.headers {
+		if h.section != section || h.subsection != subsection {
+			continue
+		}
+		next := len(f.lines)
+		if i+1 < len(f.headers) {
+			next = f.headers[i+1].line - 1
+		}
+		end = h.line
+		for line := h.line; line < next; line++ {
+			if strings.TrimSpace(f.lines[line]) != "" {
+				end = line + 1
+			}
+		}
+	}
+	return end
+}
+
+// Save 写回文件并保留原有权限，修改前的内容保存为 <文件名>.bak
+func (f *File) Save() error {
+	return fileutil.WriteAtomic(f.Path, f.lines.Bytes(), fileutil.Perm(f.Path, 0644), ".bak")
+}
@@ -0,0 +1,148 @@
+package gitconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parse 从字符串构造配置文件
+func parse(content string) *File {
+	f := &File{lines: strings.Split(strings.TrimSuffix(content, "\n"), "\n")}
+	f.reparse()
+	return f
+}
+
+// text 返回配置文件当前的内容
+func (f *File) text() string {
+	return strings.Join(f.lines, "\n") + "\n"
+}
+
+func TestInlineHeaderKeys(t *testing.T) {
+	const content = "[core] bare = false\n\tfilemode = true\n[remote \"origin\"] url = git@github.com:o/r.git\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n"
+	tests := []struct {
+		name string
+		edit func(f *File) int
+		want string
+	}{
+		{
+			name: "Set",
+			edit: func(f *File) int { f.Set("core", "", "bare", "true"); return 1 },
+			want: "[core] bare = true\n\tfilemode = true\n[remote \"origin\"] url = git@github.com:o/r.git\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+		},
+		{
+			name: "ReplaceValue",
+			edit: func(f *File) int {
+				return f.ReplaceValue("remote", "origin", "url", "git@github.com:o/r.git", "git@work:o/r.git")
+			},
+			want: "[core] bare = false\n\tfilemode = true\n[remote \"origin\"] url = git@work:o/r.git\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+		},
+		{
+			name: "Unset",
+			edit: func(f *File) int { return f.Unset("core", "", "bare") },
+			want: "[core]\n\tfilemode = true\n[remote \"origin\"] url = git@github.com:o/r.git\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+		},
+		{
+			name: "UnsetValue",
+			edit: func(f *File) int { return f.UnsetValue("remote", "origin", "url", "git@github.com:o/r.git") },
+			want: "[core] bare = false\n\tfilemode = true\n[remote \"origin\"]\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parse(content)
+			if n := tt.edit(f); n != 1 {
+				t.Errorf("修改了 %d 处，期望 1", n)
+			}
+			if got := f.text(); got != tt.want {
+				t.Errorf("结果:\n%s\n期望:\n%s", got, tt.want)
+			}
+			// 节标题保留后，其余键值仍属于原来的节
+			if value, ok := f.Get("core", "", "filemode"); !ok || value != "true" {
+				t.Errorf("core.filemode = %q, %v", value, ok)
+			}
+			if len(f.Subsections("remote")) != 1 {
+				t.Errorf("remote 子节 = %v", f.Subsections("remote"))
+			}
+		})
+	}
+}
+
+func TestUnsetValueRemovesInlineSection(t *testing.T) {
+	f := parse("[user]\n\tname = a\n[url \"git@work:\"] insteadOf = https://github.com/\n")
+	if n := f.UnsetValue("url", "git@work:", "insteadOf", "https://github.com/"); n != 1 {
+		t.Fatalf("删除了 %d 处", n)
+	}
+	if got := f.text(); got != "[user]\n\tname = a\n" {
+		t.Errorf("结果:\n%s", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	f := parse(`[Core]
+	Bare = false ; 注释
+[remote "o\"rigin"]
+	url = "git@github.com:o/r.git"  # 注释
+[branch.Main] remote = origin
+	rebase
+[user]
+	name = "A  B" C   D
+`)
+	tests := []struct {
+		section, subsection, key, want string
+	}{
+		{"core", "", "bare", "false"},
+		{"remote", `o"rigin`, "url", "git@github.com:o/r.git"},
+		{"branch", "main", "remote", "origin"},
+		{"branch", "main", "rebase", "true"},
+		{"user", "", "name", "A  B C D"},
+	}
+	for _, tt := range tests {
+		if got, ok := f.Get(tt.section, tt.subsection, tt.key); !ok || got != tt.want {
+			t.Errorf("%s.%s.%s = %q, %v，期望 %q", tt.section, tt.subsection, tt.key, got, ok, tt.want)
+		}
+	}
+}
+
+func TestSetAndAdd(t *testing.T) {
+	f := parse("[user]\n\tname = a\n\n[core]\n\tbare = false\n")
+	f.Set("user", "", "email", "a@example.com")
+	f.Set("core", "", "bare", "true")
+	f.Add("url", "git@work:", "insteadOf", "https://github.com/")
+	want := "[user]\n\tname = a\n\temail = a@example.com\n\n[core]\n\tbare = true\n[url \"git@work:\"]\n\tinsteadOf = https://github.com/\n"
+	if got := f.text(); got != want {
+		t.Errorf("结果:\n%s\n期望:\n%s", got, want)
+	}
+	if got := formatValue(" a#b\"\\"); got != `" a#b\"\\"` {
+		t.Errorf("formatValue = %s", got)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("[core] bare = false\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Set("core", "", "bare", "true")
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := loaded.Get("core", "", "bare"); value != "true" {
+		t.Errorf("core.bare = %q", value)
+	}
+	if backup, _ := os.ReadFile(path + ".bak"); string(backup) != "[core] bare = false\n" {
+		t.Errorf("备份内容 = %q", backup)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("权限 = %v", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,151 @@
+package gitconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GlobalConfigPath 返回全局 git 配置文件路径：优先 GIT_CONFIG_GLOBAL，其次 ~/.gitconfig，
+// ~/.gitconfig 不存在而 XDG 配置存在时使用 ~/.config/git/config
+func GlobalConfigPath() (string, error) {
+	if path := os.Getenv("GIT_CONFIG_GLOBAL"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("无法获取用户主目录: %w", err)
+	}
+	path := filepath.Join(home, ".gitconfig")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		xdg := os.Getenv("XDG_CONFIG_HOME")
+		if xdg == "" {
+			xdg = filepath.Join(home, ".config")
+		}
+		if _, err := os.Stat(filepath.Join(xdg, "git", "config")); err == nil {
+			return filepath.Join(xdg, "git", "config"), nil
+		}
+	}
+	return path, nil
+}
+
+// IdentityRule 表示全局配置中的一条 includeIf "gitdir:..." 规则及其引入文件中的身份设置
+type IdentityRule struct {
+	// Condition 为完整的条件，如 gitdir:~/work/
+	Condition string
+	// Include 为 path 的原始写法
+	Include    string
+	Name       string
+	Email      string
+	SSHCommand string
+	Rewrites   []URLRewrite
+}
+
+// GitDir 返回条件中的目录部分
+func (r IdentityRule) GitDir() string {
+	_, dir, _ := strings.Cut(r.Condition, ":")
+	return dir
+}
+
+// GitDirCondition 生成 gitdir 条件；目录以 / 结尾时匹配其下的所有仓库
+func GitDirCondition(dir string) string {
+	dir = filepath.ToSlash(strings.TrimSpace(dir))
+	if !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+	return "gitdir:" + dir
+}
+
+// IncludePath 将 path 的写法解析为实际路径：~ 开头的相对主目录，其他相对路径相对于所在配置文件
+func (f *File) IncludePath(include string) string {
+	if include == "~" || strings.HasPrefix(include, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, include[1:])
+		}
+	}
+	if filepath.IsAbs(include) {
+		return include
+	}
+	return filepath.Join(filepath.Dir(f.Path), include)
+}
+
+// IdentityRules 读取所有 gitdir 条件的 includeIf 规则，每个 path 对应一条规则
+func (f *File) IdentityRules() []IdentityRule {
+	var rules []IdentityRule
+	for _, condition := range f.Subsections("includeif") {
+		if !strings.HasPrefix(condition, "gitdir:") && !strings.HasPrefix(condition, "gitdir/i:") {
+			continue
+		}
+		for _, include := range f.GetAll("includeif", condition, "path") {
+			rule := IdentityRule{Condition: condition, Include: include}
+			if included, err := Load(f.IncludePath(include)); err == nil {
+				rule.Name, _ = included.Get("user", "", "name")
+				rule.Email, _ = included.Get("user", "", "email")
+				rule.SSHCommand, _ = included.Get("core", "", "sshcommand")
+				for _, base := range included.Subsections("url") {
+					for _, prefix := range included.GetAll("url", base, "insteadof") {
+						rule.Rewrites = append(rule.Rewrites, URLRewrite{Base: base, InsteadOf: prefix})
+					}
+				}
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// SaveIdentityRule 写入规则的引入文件并在全局配置中登记 includeIf；
+// old 不为空时先移除旧的条件和 URL 改写，引入文件中的其他设置保持不变
+func (f *File) SaveIdentityRule(old *IdentityRule, rule IdentityRule) error {
+	included, err := Load(f.IncludePath(rule.Include))
+	if err != nil {
+		return err
+	}
+	setOrUnset(included, "user", "name", rule.Name)
+	setOrUnset(included, "user", "email", rule.Email)
+	setOrUnset(included, "core", "sshCommand", rule.SSHCommand)
+	if old != nil {
+		for _, rewrite := range old.Rewrites {
+			included.RemoveURLRewrite(rewrite)
+		}
+	}
+	for _, rewrite := range rule.Rewrites {
+		included.AddURLRewrite(rewrite)
+	}
+	if err := included.Save(); err != nil {
+		return err
+	}
+
+	if old != nil {
+		f.UnsetValue("includeIf", old.Condition, "path", old.Include)
+	}
+	found := false
+	for _, include := range f.GetAll("includeIf", rule.Condition, "path") {
+		if include == rule.Include {
+			found = true
+		}
+	}
+	if !found {
+		f.Add("includeIf", rule.Condition, "path", rule.Include)
+	}
+	return f.Save()
+}
+
+// RemoveIdentityRule 从全局配置中移除规则，引入文件保留在磁盘上
+func (f *File) RemoveIdentityRule(rule IdentityRule) error {
+	f.UnsetValue("includeIf", rule.Condition, "path", rule.Include)
+	return f.Save()
+}
+
+// setOrUnset 值为空时删除键，否则设置
+func setOrUnset(f *File, section, key, value string) {
+	if value == "" {
+		f.Unset(section, "", key)
+		if len(f.SectionEntries(section, "")) == 0 {
+			f.RemoveSection(section, "")
+		}
+		return
+	}
+	f.Set(section, "", key, value)
+}
@@ -0,0 +1,103 @@
+package gitconfig
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Remote 表示一个 SSH 形式的 Git 远程地址
+type Remote struct {
+	User string
+	Host string
+	Port string
+	Path string
+	// URL 表示地址原本是否为 ssh:// 形式
+	URL bool
+}
+
+// ParseRemote 解析 alias:org/repo.git、git@alias:org/repo.git 和 ssh://[user@]alias[:port]/org/repo.git 三种形式，
+// 其它协议（https://、file:// 等）和本地路径返回错误
+func ParseRemote(spec string) (Remote, error) {
+	spec = strings.TrimSpace(spec)
+	if scheme, _, ok := strings.Cut(spec, "://"); ok {
+		switch scheme {
+		case "ssh", "git+ssh", "ssh+git":
+		default:
+			return Remote{}, fmt.Errorf("%s 不是 SSH 地址", spec)
+		}
+		u, err := url.Parse(spec)
+		if err != nil {
+			return Remote{}, fmt.Errorf("无效的仓库地址: %w", err)
+		}
+		r := Remote{Host: u.Hostname(), Port: u.Port(), Path: strings.TrimPrefix(u.Path, "/"), URL: true}
+		if u.User != nil {
+			r.User = u.User.Username()
+		}
+		if r.Host == "" || r.Path == "" {
+			return Remote{}, fmt.Errorf("无效的仓库地址: %s", spec)
+		}
+		return r, nil
+	}
+
+	// 与 git 相同：第一个冒号之前出现斜杠的是本地路径，单个字母加冒号是 Windows 盘符
+	host, path, ok := strings.Cut(spec, ":")
+	if !ok || host == "" || path == "" || strings.Contains(host, "/") || len(host) == 1 {
+		return Remote{}, fmt.Errorf("仓库地址应为 别名:组织/仓库.git 的形式")
+	}
+	r := Remote{Host: host, Path: path}
+	if user, h, ok := strings.Cut(host, "@"); ok {
+		r.User, r.Host = user, h
+	}
+	// [host:port]:path 形式
+	if strings.HasPrefix(r.Host, "[") && strings.HasSuffix(r.Host, "]") {
+		if h, p, err := net.SplitHostPort(r.Host[1 : len(r.Host)-1]); err == nil {
+			r.Host, r.Port = h, p
+		}
+	}
+	return r, nil
+}
+
+// String 返回 scp 形式的地址，指定了端口或原本为 ssh:// 形式时返回 ssh:// 形式
+func (r Remote) String() string {
+	user := ""
+	if r.User != "" {
+		user = r.User + "@"
+	}
+	if r.Port != "" || r.URL {
+		host := r.Host
+		if r.Port != "" {
+			host = net.JoinHostPort(r.Host, r.Port)
+		}
+		return "ssh://" + user + host + "/" + strings.TrimPrefix(r.Path, "/")
+	}
+	return user + r.Host + ":" + r.Path
+}
+
+// ParseCloneURL 解析克隆地址：SSH 地址同 ParseRemote，https:// 地址转换为同一主机上 git 用户的 SSH 地址
+func ParseCloneURL(spec string) (Remote, error) {
+	spec = strings.TrimSpace(spec)
+	if !strings.HasPrefix(spec, "https://") && !strings.HasPrefix(spec, "http://") {
+		return ParseRemote(spec)
+	}
+	u, err := url.Parse(spec)
+	if err != nil {
+		return Remote{}, fmt.Errorf("无效的仓库地址: %w", err)
+	}
+	path := strings.Trim(u.Path, "/")
+	if u.Hostname() == "" || !strings.Contains(path, "/") {
+		return Remote{}, fmt.Errorf("无效的仓库地址: %s", spec)
+	}
+	// HTTPS 端口与 SSH 端口无关，不保留
+	return Remote{User: "git", Host: u.Hostname(), Path: path}, nil
+}
+
+// RepoName 返回路径中的仓库名（去掉 .git）及其之前的各级组织名
+func (r Remote) RepoName() (owner, name string) {
+	path := strings.TrimSuffix(strings.Trim(r.Path, "/"), ".git")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return "", path
+}
@@ -0,0 +1,85 @@
+package gitconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RepoConfigPath 返回仓库的 config 文件路径，支持普通仓库、裸仓库以及 .git 为文件的工作树和子模块
+func RepoConfigPath(repo string) (string, error) {
+	abs, err := filepath.Abs(repo)
+	if err != nil {
+		return "", err
+	}
+
+	gitDir := filepath.Join(abs, ".git")
+	info, err := os.Stat(gitDir)
+	switch {
+	case err == nil && info.IsDir():
+	case err == nil:
+		// 工作树和子模块的 .git 是内容为 "gitdir: <路径>" 的文件
+		data, err := os.ReadFile(gitDir)
+		if err != nil {
+			return "", fmt.Errorf("无法读取 %s: %w", gitDir, err)
+		}
+		target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+		if !ok {
+			return "", fmt.Errorf("%s 格式无效", gitDir)
+		}
+		gitDir = strings.TrimSpace(target)
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(abs, gitDir)
+		}
+		// 附加工作树的配置保存在主仓库中
+		if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+			dir := strings.TrimSpace(string(common))
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(gitDir, dir)
+			}
+			gitDir = dir
+		}
+	case isBareRepo(abs):
+		gitDir = abs
+	default:
+		return "", fmt.Errorf("%s 不是 Git 仓库", repo)
+	}
+
+	path := filepath.Join(gitDir, "config")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("找不到仓库配置 %s", path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// isBareRepo 判断目录是否为裸仓库
+func isBareRepo(dir string) bool {
+	for _, name := range []string{"HEAD", "config", "objects"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoteURL 表示仓库配置中的一个远程地址
+type RemoteURL struct {
+	Name string
+	// Key 为 url 或 pushurl
+	Key string
+	URL string
+}
+
+// RemoteURLs 返回配置中所有远程仓库的 url 和 pushurl
+func (f *File) RemoteURLs() []RemoteURL {
+	var result []RemoteURL
+	for _, name := range f.Subsections("remote") {
+		for _, key := range []string{"url", "pushurl"} {
+			for _, value := range f.GetAll("remote", name, key) {
+				result = append(result, RemoteURL{Name: name, Key: key, URL: value})
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package gitconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// URLRewrite 表示一条 url.<base>.insteadOf（Push 为 true 时为 pushInsteadOf）规则：
+// 以 InsteadOf 开头的地址会被替换为以 Base 开头
+type URLRewrite struct {
+	Base      string
+	InsteadOf string
+	Push      bool
+}
+
+// Key 返回规则使用的配置键名
+func (r URLRewrite) Key() string {
+	if r.Push {
+		return "pushInsteadOf"
+	}
+	return "insteadOf"
+}
+
+// Apply 按 git 的规则改写地址，不匹配时返回原地址
+func (r URLRewrite) Apply(url string) (string, bool) {
+	if rest, ok := strings.CutPrefix(url, r.InsteadOf); ok {
+		return r.Base + rest, true
+	}
+	return url, false
+}
+
+// URLRewrites 返回配置文件中的所有 insteadOf 和 pushInsteadOf 规则
+func (f *File) URLRewrites() []URLRewrite {
+	var rewrites []URLRewrite
+	for _, base := range f.Subsections("url") {
+		for _, prefix := range f.GetAll("url", base, "insteadof") {
+			rewrites = append(rewrites, URLRewrite{Base: base, InsteadOf: prefix})
+		}
+		for _, prefix := range f.GetAll("url", base, "pushinsteadof") {
+			rewrites = append(rewrites, URLRewrite{Base: base, InsteadOf: prefix, Push: true})
+		}
+	}
+	return rewrites
+}
+
+// AddURLRewrite 添加一条规则，已存在时不重复添加
+func (f *File) AddURLRewrite(r URLRewrite) {
+	for _, prefix := range f.GetAll("url", r.Base, r.Key()) {
+		if prefix == r.InsteadOf {
+			return
+		}
+	}
+	f.Add("url", r.Base, r.Key(), r.InsteadOf)
+}
+
+// RemoveURLRewrite 删除一条规则，url 节因此变空时一并删除
+func (f *File) RemoveURLRewrite(r URLRewrite) {
+	f.UnsetValue("url", r.Base, r.Key(), r.InsteadOf)
+}
+
+// RewriteConflict 检查新规则与已有规则是否冲突：同一类型下相同前缀只能改写到一个地址，
+// 否则 git 的选择取决于配置顺序。前缀互相包含时 git 使用最长的前缀，不算冲突。
+func RewriteConflict(existing []URLRewrite, r URLRewrite) error {
+	for _, other := range existing {
+		if other.Push != r.Push || other.InsteadOf != r.InsteadOf {
+			continue
+		}
+		if other.Base == r.Base {
+			return fmt.Errorf("已存在相同的规则")
+		}
+		return fmt.Errorf("前缀 %s 已被改写为 %s，同一前缀只能对应一个地址", r.InsteadOf, other.Base)
+	}
+	return nil
+}
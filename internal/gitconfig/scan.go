@@ -0,0 +1,75 @@
+package gitconfig
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultScanDepth 扫描工作区时默认的最大目录深度
+const DefaultScanDepth = 6
+
+// skipDirs 扫描时跳过的目录，通常体积很大且不包含需要管理的仓库
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	".cache":       true,
+	"Library":      true,
+}
+
+// Repo 表示扫描到的一个仓库
+type Repo struct {
+	Path       string
+	ConfigPath string
+	Remotes    []RemoteURL
+}
+
+// ScanRepos 在目录下查找 Git 仓库（含裸仓库），找到仓库后不再进入其子目录；无权访问的目录会被跳过
+func ScanRepos(ctx context.Context, root string, maxDepth int) ([]Repo, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	var repos []Repo
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root {
+			name := d.Name()
+			if skipDirs[name] || strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			rel, _ := filepath.Rel(root, path)
+			if strings.Count(rel, string(filepath.Separator))+1 > maxDepth {
+				return filepath.SkipDir
+			}
+		}
+
+		_, gitErr := os.Stat(filepath.Join(path, ".git"))
+		if gitErr != nil && !isBareRepo(path) {
+			return nil
+		}
+		if configPath, err := RepoConfigPath(path); err == nil {
+			repo := Repo{Path: path, ConfigPath: configPath}
+			if file, err := Load(configPath); err == nil {
+				repo.Remotes = file.RemoteURLs()
+			}
+			repos = append(repos, repo)
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return repos, err
+	}
+	return repos, nil
+}
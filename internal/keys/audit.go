@@ -0,0 +1,64 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// MinRSABits RSA 密钥的最小安全长度
+const MinRSABits = 2048
+
+// standardCurves OpenSSH 支持的 ECDSA 曲线
+var standardCurves = map[string]bool{
+	"P-256": true,
+	"P-384": true,
+	"P-521": true,
+}
+
+// Audit 检查密钥的算法强度和存储方式，返回发现的问题
+func Audit(info *KeyInfo) []string {
+	var findings []string
+
+	if info.Format == FormatPEM {
+		findings = append(findings, "旧式 PEM 格式私钥，建议用 ssh-keygen -p 转换为 OpenSSH 格式")
+	}
+
+	if info.PublicKey != nil {
+		switch info.Type {
+		case ssh.KeyAlgoDSA:
+			findings = append(findings, "DSA 密钥已被 OpenSSH 弃用，请更换为 ED25519")
+		case ssh.KeyAlgoRSA:
+			if info.Bits < MinRSABits {
+				findings = append(findings, fmt.Sprintf("RSA 密钥仅 %d 位，低于 %d 位", info.Bits, MinRSABits))
+			}
+		}
+	}
+	if info.Curve != "" && !standardCurves[info.Curve] {
+		findings = append(findings, fmt.Sprintf("ECDSA 密钥使用了非常规曲线 %s，OpenSSH 无法使用", info.Curve))
+	}
+
+	// 公钥文件和 PuTTY 密钥无法判断是否加密
+	switch info.Format {
+	case FormatOpenSSH, FormatPEM, FormatPKCS8:
+		if !info.Encrypted {
+			findings = append(findings, "私钥未设置口令")
+		}
+	}
+
+	return findings
+}
+
+// ecdsaCurve 返回 ECDSA 公钥使用的曲线名称，非 ECDSA 密钥返回空字符串
+func ecdsaCurve(pub ssh.PublicKey) string {
+	cpk, ok := pub.(ssh.CryptoPublicKey)
+	if !ok {
+		return ""
+	}
+	key, ok := cpk.CryptoPublicKey().(*ecdsa.PublicKey)
+	if !ok {
+		return ""
+	}
+	return key.Curve.Params().Name
+}
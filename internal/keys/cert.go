@@ -0,0 +1,157 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// CertExpiryWarning 证书剩余有效期低于该值时给出提示
+const CertExpiryWarning = 7 * 24 * time.Hour
+
+// CertInfo 描述一个 OpenSSH 证书
+type CertInfo struct {
+	Path            string
+	Cert            *ssh.Certificate
+	Comment         string
+	KeyID           string
+	Serial          uint64
+	CertType        string
+	Principals      []string
+	ValidAfter      time.Time
+	ValidBefore     time.Time
+	Forever         bool
+	Extensions      []string
+	CriticalOptions []string
+	KeyFingerprint  string
+	CAFingerprint   string
+}
+
+// CertPathFor 返回 OpenSSH 默认为私钥加载的证书路径
+func CertPathFor(identityFile string) string {
+	return identityFile + "-cert.pub"
+}
+
+// ParseCertificate 读取并解析 *-cert.pub 证书文件
+func ParseCertificate(path string) (*CertInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取证书文件: %w", err)
+	}
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析证书: %w", err)
+	}
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("%s 不是 OpenSSH 证书", path)
+	}
+
+	info := NewCertInfo(cert)
+	info.Path = path
+	info.Comment = comment
+	return info, nil
+}
+
+// NewCertInfo 从证书提取显示所需的信息
+func NewCertInfo(cert *ssh.Certificate) *CertInfo {
+	info := &CertInfo{
+		Cert:           cert,
+		KeyID:          cert.KeyId,
+		Serial:         cert.Serial,
+		CertType:       "用户证书",
+		Principals:     cert.ValidPrincipals,
+		ValidAfter:     time.Unix(int64(cert.ValidAfter), 0),
+		Forever:        cert.ValidBefore == ssh.CertTimeInfinity,
+		Extensions:     sortedKeys(cert.Extensions),
+		KeyFingerprint: ssh.FingerprintSHA256(cert.Key),
+		CAFingerprint:  ssh.FingerprintSHA256(cert.SignatureKey),
+	}
+	if cert.CertType == ssh.HostCert {
+		info.CertType = "主机证书"
+	}
+	if !info.Forever {
+		info.ValidBefore = time.Unix(int64(cert.ValidBefore), 0)
+	}
+	for _, name := range sortedKeys(cert.CriticalOptions) {
+		option := name
+		if value := cert.CriticalOptions[name]; value != "" {
+			option += "=" + value
+		}
+		info.CriticalOptions = append(info.CriticalOptions, option)
+	}
+	return info
+}
+
+// Validity 返回可读的有效期描述
+func (c *CertInfo) Validity() string {
+	const layout = "2006-01-02 15:04"
+	from := c.ValidAfter.Local().Format(layout)
+	if c.Cert.ValidAfter == 0 {
+		from = "始终"
+	}
+	if c.Forever {
+		return from + " 起永久有效"
+	}
+	return fmt.Sprintf("%s 至 %s", from, c.ValidBefore.Local().Format(layout))
+}
+
+// Check 检查证书在指定时间是否有效，返回需要提示的问题
+func (c *CertInfo) Check(now time.Time) []string {
+	switch {
+	case now.Before(c.ValidAfter):
+		return []string{fmt.Sprintf("证书尚未生效（%s 起）", c.ValidAfter.Local().Format("2006-01-02 15:04"))}
+	case c.Forever:
+		return nil
+	case !now.Before(c.ValidBefore):
+		return []string{fmt.Sprintf("证书已于 %s 过期", c.ValidBefore.Local().Format("2006-01-02 15:04"))}
+	case c.ValidBefore.Sub(now) < CertExpiryWarning:
+		return []string{fmt.Sprintf("证书将在 %s 后过期", formatDuration(c.ValidBefore.Sub(now)))}
+	}
+	return nil
+}
+
+// formatDuration 将时间间隔格式化为天或小时
+func formatDuration(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%d 天", int(d.Hours()/24))
+	}
+	if d >= time.Hour {
+		return fmt.Sprintf("%d 小时", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d 分钟", int(d.Minutes()))
+}
+
+// sortedKeys 返回按字母排序的 map 键
+func sortedKeys(m map[string]string) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// String 返回证书的多行描述，用于详情界面
+func (c *CertInfo) String() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("类型: %s (%s)\n", c.CertType, KeyTypeName(c.Cert.Key.Type())))
+	b.WriteString(fmt.Sprintf("Key ID: %s\n", c.KeyID))
+	b.WriteString(fmt.Sprintf("序列号: %d\n", c.Serial))
+	principals := strings.Join(c.Principals, ", ")
+	if principals == "" {
+		principals = "（任意）"
+	}
+	b.WriteString(fmt.Sprintf("Principals: %s\n", principals))
+	b.WriteString(fmt.Sprintf("有效期: %s\n", c.Validity()))
+	if len(c.CriticalOptions) > 0 {
+		b.WriteString(fmt.Sprintf("关键选项: %s\n", strings.Join(c.CriticalOptions, ", ")))
+	}
+	b.WriteString(fmt.Sprintf("扩展: %s\n", strings.Join(c.Extensions, ", ")))
+	b.WriteString(fmt.Sprintf("CA 指纹: %s", c.CAFingerprint))
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ExportFormat 表示公钥导出格式
+type ExportFormat int
+
+const (
+	ExportAuthorizedKeys ExportFormat = iota
+	ExportRFC4716
+	ExportPKCS8
+)
+
+// ExportFormats 按界面显示顺序列出所有导出格式
+var ExportFormats = []ExportFormat{ExportAuthorizedKeys, ExportRFC4716, ExportPKCS8}
+
+// String 返回导出格式的显示名称
+func (f ExportFormat) String() string {
+	switch f {
+	case ExportAuthorizedKeys:
+		return "authorized_keys"
+	case ExportRFC4716:
+		return "RFC4716"
+	case ExportPKCS8:
+		return "PKCS8/PEM"
+	}
+	return "未知"
+}
+
+// FileSuffix 返回导出文件相对于私钥路径的后缀
+func (f ExportFormat) FileSuffix() string {
+	switch f {
+	case ExportRFC4716:
+		return ".rfc4716.pub"
+	case ExportPKCS8:
+		return ".pkcs8.pem"
+	}
+	return ".pub"
+}
+
+// PublicKey 返回私钥对应的公钥及注释，.pub 文件缺失时从私钥重新生成并写回 .pub 文件
+func PublicKey(path string) (ssh.PublicKey, string, error) {
+	if data, err := os.ReadFile(path + ".pub"); err == nil {
+		pub, comment, _, _, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("无法解析公钥文件: %w", err)
+		}
+		return pub, comment, nil
+	}
+
+	info, err := Inspect(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if info.Format == FormatPublic {
+		return info.PublicKey, info.Comment, nil
+	}
+	if info.PublicKey == nil {
+		return nil, "", fmt.Errorf("缺少 %s.pub，且无法在不解密的情况下从该私钥读取公钥", path)
+	}
+
+	if err := os.WriteFile(path+".pub", ssh.MarshalAuthorizedKey(info.PublicKey), 0644); err != nil {
+		return nil, "", fmt.Errorf("无法写入公钥文件: %w", err)
+	}
+	return info.PublicKey, info.Comment, nil
+}
+
+// EncodePublicKey 将公钥编码为指定格式
+func EncodePublicKey(pub ssh.PublicKey, comment string, format ExportFormat) (string, error) {
+	switch format {
+	case ExportAuthorizedKeys:
+		line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+		if comment != "" {
+			line += " " + comment
+		}
+		return line + "\n", nil
+	case ExportRFC4716:
+		return encodeRFC4716(pub, comment), nil
+	case ExportPKCS8:
+		cpk, ok := pub.(ssh.CryptoPublicKey)
+		if !ok {
+			return "", fmt.Errorf("%s 类型的密钥不支持导出为 PKCS8", pub.Type())
+		}
+		der, err := x509.MarshalPKIXPublicKey(cpk.CryptoPublicKey())
+		if err != nil {
+			return "", fmt.Errorf("无法编码公钥: %w", err)
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+	}
+	return "", fmt.Errorf("未知的导出格式")
+}
+
+// encodeRFC4716 按 RFC4716 (SSH2 公钥文件格式) 编码公钥，每行不超过 70 个字符
+func encodeRFC4716(pub ssh.PublicKey, comment string) string {
+	var buf bytes.Buffer
+	buf.WriteString("---- BEGIN SSH2 PUBLIC KEY ----\n")
+	if comment != "" {
+		buf.WriteString(fmt.Sprintf("Comment: \"%s\"\n", strings.ReplaceAll(comment, "\"", "\\\"")))
+	}
+	body := base64.StdEncoding.EncodeToString(pub.Marshal())
+	for len(body) > 70 {
+		buf.WriteString(body[:70])
+		buf.WriteString("\n")
+		body = body[70:]
+	}
+	buf.WriteString(body)
+	buf.WriteString("\n---- END SSH2 PUBLIC KEY ----\n")
+	return buf.String()
+}
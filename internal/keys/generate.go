@@ -0,0 +1,78 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/allanpk716/git_ssh_tui/internal/fileutil"
+	"golang.org/x/crypto/ssh"
+)
+
+// 可生成的密钥类型
+const (
+	GenerateEd25519 = "ed25519"
+	GenerateRSA     = "rsa"
+)
+
+// GenerateRSABits 生成 RSA 密钥时使用的位数
+const GenerateRSABits = 4096
+
+// Generate 生成新的密钥对，以 OpenSSH 格式写入 path 和 path.pub。
+// passphrase 为空时私钥不加密；任一文件已存在时不会覆盖。
+func Generate(path, keyType, comment string, passphrase []byte) (ssh.PublicKey, error) {
+	for _, p := range []string{path, path + ".pub"} {
+		if _, err := os.Stat(p); err == nil {
+			return nil, fmt.Errorf("%s 已存在", p)
+		}
+	}
+
+	var key crypto.PrivateKey
+	switch keyType {
+	case GenerateEd25519:
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("无法生成密钥: %w", err)
+		}
+		key = priv
+	case GenerateRSA:
+		priv, err := rsa.GenerateKey(rand.Reader, GenerateRSABits)
+		if err != nil {
+			return nil, fmt.Errorf("无法生成密钥: %w", err)
+		}
+		key = priv
+	default:
+		return nil, fmt.Errorf("不支持的密钥类型: %s", keyType)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("无法生成密钥: %w", err)
+	}
+	var block *pem.Block
+	if len(passphrase) == 0 {
+		block, err = ssh.MarshalPrivateKey(key, comment)
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, comment, passphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("无法编码私钥: %w", err)
+	}
+
+	if err := fileutil.WriteAtomic(path, pem.EncodeToMemory(block), 0600, ""); err != nil {
+		return nil, err
+	}
+	pub := signer.PublicKey()
+	line, err := EncodePublicKey(pub, comment, ExportAuthorizedKeys)
+	if err != nil {
+		return nil, err
+	}
+	if err := fileutil.WriteAtomic(path+".pub", []byte(line), 0644, ""); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
@@ -0,0 +1,195 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// 私钥文件格式
+const (
+	FormatOpenSSH = "OpenSSH"
+	FormatPEM     = "PEM"
+	FormatPKCS8   = "PKCS8"
+	FormatPuTTY   = "PuTTY"
+	FormatPublic  = "公钥"
+	FormatUnknown = "未知"
+)
+
+// KeyInfo 描述一个密钥文件的元数据
+type KeyInfo struct {
+	Path        string
+	Format      string
+	Type        string
+	Bits        int
+	Curve       string
+	Fingerprint string
+	Comment     string
+	Encrypted   bool
+	PublicKey   ssh.PublicKey
+}
+
+// Summary 返回密钥的一行摘要，例如 "ED25519 256 SHA256:xxx"
+func (k *KeyInfo) Summary() string {
+	if k.PublicKey == nil {
+		return k.Format
+	}
+	return fmt.Sprintf("%s %d %s", KeyTypeName(k.Type), k.Bits, k.Fingerprint)
+}
+
+// Inspect 读取密钥文件并解析其元数据，私钥加密时尝试从同名 .pub 文件补全公钥信息
+func Inspect(path string) (*KeyInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取密钥文件: %w", err)
+	}
+
+	info := &KeyInfo{Path: path, Format: detectFormat(data)}
+
+	switch info.Format {
+	case FormatPuTTY:
+		return info, nil
+	case FormatPublic:
+		pub, comment, _, _, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析公钥: %w", err)
+		}
+		info.setPublicKey(pub)
+		info.Comment = comment
+		return info, nil
+	case FormatUnknown:
+		return info, nil
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case bytes.Contains(data, []byte("ENCRYPTED PRIVATE KEY")):
+		// 加密的 PKCS8 私钥无法在不解密的情况下读取公钥
+		info.Encrypted = true
+	case err == nil:
+		info.setPublicKey(signer.PublicKey())
+	case errors.As(err, &missing):
+		info.Encrypted = true
+		if missing.PublicKey != nil {
+			info.setPublicKey(missing.PublicKey)
+		}
+	default:
+		// ssh 包不支持的 ECDSA 曲线仍可由 x509 解析，用于安全审计
+		if ecKey, ecErr := parseECPrivateKey(data); ecErr == nil {
+			info.Type = "ecdsa"
+			info.Curve = ecKey.Curve.Params().Name
+			info.Bits = ecKey.Curve.Params().BitSize
+			return info, nil
+		}
+		return nil, fmt.Errorf("无法解析私钥: %w", err)
+	}
+
+	// 私钥本身不带注释（或加密的 PEM 私钥无法读出公钥），从 .pub 文件中补全
+	if pubData, err := os.ReadFile(path + ".pub"); err == nil {
+		if pub, comment, _, _, err := ssh.ParseAuthorizedKey(pubData); err == nil {
+			if info.PublicKey == nil {
+				info.setPublicKey(pub)
+			}
+			info.Comment = comment
+		}
+	}
+
+	return info, nil
+}
+
+// setPublicKey 根据公钥填充类型、长度和指纹
+func (k *KeyInfo) setPublicKey(pub ssh.PublicKey) {
+	k.PublicKey = pub
+	k.Type = pub.Type()
+	k.Fingerprint = ssh.FingerprintSHA256(pub)
+	k.Bits = KeyBits(pub)
+	k.Curve = ecdsaCurve(pub)
+}
+
+// parseECPrivateKey 解析未加密的 PEM 格式 ECDSA 私钥
+func parseECPrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, fmt.Errorf("不是 EC 私钥")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// KeyBits 返回公钥的位数
+func KeyBits(pub ssh.PublicKey) int {
+	if cert, ok := pub.(*ssh.Certificate); ok {
+		pub = cert.Key
+	}
+	cpk, ok := pub.(ssh.CryptoPublicKey)
+	if !ok {
+		return 0
+	}
+	switch key := cpk.CryptoPublicKey().(type) {
+	case *rsa.PublicKey:
+		return key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return key.Curve.Params().BitSize
+	case *dsa.PublicKey:
+		return key.P.BitLen()
+	}
+	// ed25519 及其 FIDO 变体固定为 256 位
+	if strings.Contains(pub.Type(), "ed25519") {
+		return 256
+	}
+	return 0
+}
+
+// KeyTypeName 将 SSH 算法名转换为常见的简短名称
+func KeyTypeName(keyType string) string {
+	switch keyType {
+	case ssh.KeyAlgoRSA:
+		return "RSA"
+	case ssh.KeyAlgoDSA:
+		return "DSA"
+	case ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+		return "ECDSA"
+	case ssh.KeyAlgoED25519:
+		return "ED25519"
+	case ssh.KeyAlgoSKECDSA256:
+		return "ECDSA-SK"
+	case ssh.KeyAlgoSKED25519:
+		return "ED25519-SK"
+	}
+	return keyType
+}
+
+// detectFormat 根据文件内容判断密钥格式
+func detectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("PuTTY-User-Key-File")) {
+		return FormatPuTTY
+	}
+
+	block, _ := pem.Decode(trimmed)
+	if block == nil {
+		if _, _, _, _, err := ssh.ParseAuthorizedKey(trimmed); err == nil {
+			return FormatPublic
+		}
+		return FormatUnknown
+	}
+
+	switch block.Type {
+	case "OPENSSH PRIVATE KEY":
+		return FormatOpenSSH
+	case "PRIVATE KEY", "ENCRYPTED PRIVATE KEY":
+		return FormatPKCS8
+	case "RSA PRIVATE KEY", "DSA PRIVATE KEY", "EC PRIVATE KEY":
+		return FormatPEM
+	}
+	return FormatUnknown
+}
@@ -0,0 +1,37 @@
+package keys
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Scan 扫描目录中的私钥文件，跳过公钥、known_hosts 等非私钥文件
+func Scan(dir string) ([]*KeyInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*KeyInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, ".pub") || strings.HasSuffix(name, ".bak") || strings.HasPrefix(name, "known_hosts") {
+			continue
+		}
+		info, err := Inspect(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		switch info.Format {
+		case FormatOpenSSH, FormatPEM, FormatPKCS8, FormatPuTTY:
+			result = append(result, info)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
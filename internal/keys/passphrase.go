@@ -0,0 +1,87 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/allanpk716/git_ssh_tui/internal/fileutil"
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrWrongPassphrase 表示提供的口令无法解密私钥
+var ErrWrongPassphrase = errors.New("口令错误")
+
+// ErrEncryptedPKCS8 表示私钥是加密的 PKCS8 格式，golang.org/x/crypto/ssh 无法解密
+var ErrEncryptedPKCS8 = errors.New("不支持加密的 PKCS8 私钥，请先用 ssh-keygen -p 或 openssl pkcs8 转换")
+
+// ParsePrivateKey 解析私钥，passphrase 为空时按未加密私钥处理
+func ParsePrivateKey(data, passphrase []byte) (crypto.PrivateKey, error) {
+	if block, _ := pem.Decode(data); block != nil && block.Type == "ENCRYPTED PRIVATE KEY" {
+		return nil, ErrEncryptedPKCS8
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	if len(passphrase) == 0 {
+		key, err = ssh.ParseRawPrivateKey(data)
+	} else {
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(data, passphrase)
+	}
+
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &missing):
+		return nil, fmt.Errorf("私钥已加密，请输入当前口令")
+	case errors.Is(err, x509.IncorrectPasswordError):
+		return nil, ErrWrongPassphrase
+	case err != nil:
+		return nil, fmt.Errorf("无法解析私钥: %w", err)
+	}
+	return key, nil
+}
+
+// ChangePassphrase 设置、修改或移除私钥口令。newPassphrase 为空时移除口令。
+// 私钥会以 OpenSSH 格式原地重写，原文件先备份，返回备份文件路径。
+func ChangePassphrase(path string, oldPassphrase, newPassphrase []byte) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("无法读取私钥: %w", err)
+	}
+
+	key, err := ParsePrivateKey(data, oldPassphrase)
+	if err != nil {
+		return "", err
+	}
+
+	// OpenSSH 私钥中的注释在解析时会丢失，从 .pub 文件中恢复
+	comment := ""
+	if pubData, err := os.ReadFile(path + ".pub"); err == nil {
+		if _, c, _, _, err := ssh.ParseAuthorizedKey(pubData); err == nil {
+			comment = c
+		}
+	}
+
+	var block *pem.Block
+	if len(newPassphrase) == 0 {
+		block, err = ssh.MarshalPrivateKey(key, comment)
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, comment, newPassphrase)
+	}
+	if err != nil {
+		return "", fmt.Errorf("无法编码私钥: %w", err)
+	}
+
+	// 备份文件名带时间戳，多次修改不会覆盖最初的私钥
+	suffix := "." + time.Now().Format("20060102-150405") + ".bak"
+	if err := fileutil.WriteAtomic(path, pem.EncodeToMemory(block), 0600, suffix); err != nil {
+		return "", err
+	}
+	return path + suffix, nil
+}
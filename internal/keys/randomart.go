@@ -0,0 +1,72 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// randomart 图案的尺寸与字符表，与 OpenSSH 的 ssh-keygen -lv 保持一致
+const (
+	randomartWidth  = 17
+	randomartHeight = 9
+	randomartChars  = " .o+=*BOX@%&#/^SE"
+)
+
+// Randomart 按 OpenSSH 的 "drunken bishop" 算法生成公钥 SHA256 指纹的图案，便于肉眼比对
+func Randomart(pub ssh.PublicKey) string {
+	digest := sha256.Sum256(pub.Marshal())
+	maxValue := len(randomartChars) - 1
+
+	var field [randomartWidth][randomartHeight]int
+	x, y := randomartWidth/2, randomartHeight/2
+	for _, b := range digest {
+		for i := 0; i < 4; i++ {
+			if b&0x1 != 0 {
+				x++
+			} else {
+				x--
+			}
+			if b&0x2 != 0 {
+				y++
+			} else {
+				y--
+			}
+			x = min(max(x, 0), randomartWidth-1)
+			y = min(max(y, 0), randomartHeight-1)
+			if field[x][y] < maxValue-2 {
+				field[x][y]++
+			}
+			b >>= 2
+		}
+	}
+	field[randomartWidth/2][randomartHeight/2] = maxValue - 1
+	field[x][y] = maxValue
+
+	title := fmt.Sprintf("[%s %d]", KeyTypeName(pub.Type()), KeyBits(pub))
+	if len(title) > randomartWidth-2 {
+		title = fmt.Sprintf("[%s]", KeyTypeName(pub.Type()))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(randomartBorder(title))
+	sb.WriteString("\n")
+	for row := 0; row < randomartHeight; row++ {
+		sb.WriteString("|")
+		for col := 0; col < randomartWidth; col++ {
+			sb.WriteByte(randomartChars[min(field[col][row], maxValue)])
+		}
+		sb.WriteString("|\n")
+	}
+	sb.WriteString(randomartBorder("[SHA256]"))
+	return sb.String()
+}
+
+// randomartBorder 生成居中显示标签的边框
+func randomartBorder(label string) string {
+	left := (randomartWidth - len(label)) / 2
+	right := randomartWidth - left - len(label)
+	return "+" + strings.Repeat("-", left) + label + strings.Repeat("-", right) + "+"
+}
@@ -0,0 +1,144 @@
+package keys
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// DefaultExtensions 与 ssh-keygen 默认签发的用户证书扩展一致
+var DefaultExtensions = []string{
+	"permit-X11-forwarding",
+	"permit-agent-forwarding",
+	"permit-port-forwarding",
+	"permit-pty",
+	"permit-user-rc",
+}
+
+// SignOptions 证书签发参数
+type SignOptions struct {
+	KeyID       string
+	Principals  []string
+	ValidAfter  time.Time
+	ValidBefore time.Time // 零值表示永久有效
+	Extensions  []string
+}
+
+// ParseValidity 解析有效期，支持 "+52w"、"+30d"、"+12h"、"+90m" 形式的相对时长以及 "forever"
+func ParseValidity(spec string, now time.Time) (time.Time, error) {
+	spec = strings.TrimSpace(strings.ToLower(spec))
+	if spec == "" || spec == "forever" {
+		return time.Time{}, nil
+	}
+	spec = strings.TrimPrefix(spec, "+")
+	if len(spec) < 2 {
+		return time.Time{}, fmt.Errorf("无效的有效期: %s", spec)
+	}
+
+	n, err := strconv.Atoi(spec[:len(spec)-1])
+	if err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("无效的有效期: %s", spec)
+	}
+	var unit time.Duration
+	switch spec[len(spec)-1] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return time.Time{}, fmt.Errorf("无效的有效期单位: %s（支持 m/h/d/w）", spec)
+	}
+	return now.Add(time.Duration(n) * unit), nil
+}
+
+// LoadSigner 读取私钥并创建签名器，RSA 私钥使用 rsa-sha2-512 签名
+func LoadSigner(path string, passphrase []byte) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取私钥: %w", err)
+	}
+	key, err := ParsePrivateKey(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("无法创建签名器: %w", err)
+	}
+	if signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		if algSigner, ok := signer.(ssh.AlgorithmSigner); ok {
+			return ssh.NewSignerWithAlgorithms(algSigner, []string{ssh.KeyAlgoRSASHA512})
+		}
+	}
+	return signer, nil
+}
+
+// SignUserKey 使用 CA 签名器为用户公钥签发证书
+func SignUserKey(ca ssh.Signer, pub ssh.PublicKey, opts SignOptions) (*ssh.Certificate, error) {
+	if len(opts.Principals) == 0 {
+		return nil, fmt.Errorf("至少需要一个 principal")
+	}
+
+	var serial [8]byte
+	if _, err := rand.Read(serial[:]); err != nil {
+		return nil, err
+	}
+
+	cert := &ssh.Certificate{
+		Key:             pub,
+		Serial:          binary.BigEndian.Uint64(serial[:]),
+		CertType:        ssh.UserCert,
+		KeyId:           opts.KeyID,
+		ValidPrincipals: opts.Principals,
+		ValidAfter:      uint64(opts.ValidAfter.Unix()),
+		ValidBefore:     ssh.CertTimeInfinity,
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{},
+		},
+	}
+	if !opts.ValidBefore.IsZero() {
+		cert.ValidBefore = uint64(opts.ValidBefore.Unix())
+	}
+	for _, ext := range opts.Extensions {
+		cert.Permissions.Extensions[ext] = ""
+	}
+
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		return nil, fmt.Errorf("签发证书失败: %w", err)
+	}
+	return cert, nil
+}
+
+// SignKeyFile 为私钥（或其 .pub 文件）签发证书并写入 <私钥>-cert.pub，返回证书路径
+func SignKeyFile(ca ssh.Signer, keyPath string, opts SignOptions) (string, error) {
+	keyPath = strings.TrimSuffix(keyPath, ".pub")
+	pub, comment, err := PublicKey(keyPath)
+	if err != nil {
+		return "", err
+	}
+
+	cert, err := SignUserKey(ca, pub, opts)
+	if err != nil {
+		return "", err
+	}
+
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(cert)))
+	if comment != "" {
+		line += " " + comment
+	}
+	certPath := CertPathFor(keyPath)
+	if err := os.WriteFile(certPath, []byte(line+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("无法写入证书: %w", err)
+	}
+	return certPath, nil
+}
@@ -0,0 +1,316 @@
+package knownhosts
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/fileutil"
+	"golang.org/x/crypto/ssh"
+	xknownhosts "golang.org/x/crypto/ssh/knownhosts"
+)
+
+// 行首标记
+const (
+	MarkerCertAuthority = "@cert-authority"
+	MarkerRevoked       = "@revoked"
+)
+
+// Entry 表示 known_hosts 中的一行主机密钥记录
+type Entry struct {
+	Line        int
+	Marker      string
+	Patterns    []string
+	Key         ssh.PublicKey
+	Comment     string
+	Fingerprint string
+}
+
+// Hashed 判断该记录的主机名是否经过哈希处理
+func (e Entry) Hashed() bool {
+	return len(e.Patterns) == 1 && strings.HasPrefix(e.Patterns[0], "|1|")
+}
+
+// HostsString 返回记录中主机名的显示形式
+func (e Entry) HostsString() string {
+	if e.Hashed() {
+		return "（已哈希的主机名）"
+	}
+	return strings.Join(e.Patterns, ",")
+}
+
+// Matches 判断记录是否适用于指定地址，address 应为 Address 返回的形式
+func (e Entry) Matches(address string) bool {
+	return matchPatterns(e.Patterns, address)
+}
+
+// File 表示一个 known_hosts 文件，保留原始行以便安全地改写
+type File struct {
+	Path    string
+	lines   fileutil.Lines
+	Entries []Entry
+}
+
+// DefaultPath 返回 ~/.ssh/known_hosts 路径
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".ssh", "known_hosts"), nil
+}
+
+// Address 按 known_hosts 规则生成地址，非 22 端口使用 [host]:port 形式
+func Address(host, port string) string {
+	if port == "" || port == "22" {
+		return host
+	}
+	return "[" + host + "]:" + port
+}
+
+// Load 读取 known_hosts 文件，文件不存在时返回空文件
+func Load(path string) (*File, error) {
+	lines, err := fileutil.ReadLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 known_hosts: %w", err)
+	}
+	f := &File{Path: path, lines: lines}
+	f.reparse()
+	return f, nil
+}
+
+// parseLine 解析单行记录，注释、空行和无法解析的行返回 false
+func parseLine(line string) (Entry, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return Entry{}, false
+	}
+
+	var entry Entry
+	if strings.HasPrefix(fields[0], "@") {
+		entry.Marker = fields[0]
+		fields = fields[1:]
+	}
+	if len(fields) < 3 {
+		return Entry{}, false
+	}
+
+	entry.Patterns = strings.Split(fields[0], ",")
+	key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.Join(fields[1:], " ")))
+	if err != nil {
+		return Entry{}, false
+	}
+	entry.Key = key
+	entry.Comment = comment
+	entry.Fingerprint = ssh.FingerprintSHA256(key)
+	return entry, true
+}
+
+// Find 返回适用于指定主机和端口的记录
+func (f *File) Find(host, port string) []Entry {
+	address := Address(host, port)
+	var result []Entry
+	for _, entry := range f.Entries {
+		if entry.Matches(address) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// RemoveHost 删除明确写有指定主机和端口的普通记录（不含 @cert-authority、@revoked 和通配符记录）。
+// 多个主机共用的行只去掉匹配的主机名，返回受影响的行数。
+func (f *File) RemoveHost(host, port string) int {
+	address := Address(host, port)
+	removed := 0
+	for _, entry := range f.Entries {
+		if entry.Marker != "" || !entry.literalMatch(address) {
+			continue
+		}
+		f.removePatterns(entry, address)
+		removed++
+	}
+	f.reparse()
+	return removed
+}
+
+// RemoveEntry 删除一条记录；如指定了 address，只去掉行中与之完全相同的主机名。
+// 该行只通过通配符或否定模式匹配 address 时不做修改并返回 false
+func (f *File) RemoveEntry(entry Entry, address string) bool {
+	if address == "" {
+		f.lines.Delete(entry.Line)
+	} else if entry.literalMatch(address) {
+		f.removePatterns(entry, address)
+	} else {
+		return false
+	}
+	f.reparse()
+	return true
+}
+
+// literalMatch 判断记录中是否有与地址完全相同（或哈希后相同）的主机名
+func (e Entry) literalMatch(address string) bool {
+	for _, pattern := range e.Patterns {
+		if literalPattern(pattern, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// literalPattern 判断单个主机名是否与地址完全相同，通配符和否定模式不算
+func literalPattern(pattern, address string) bool {
+	if strings.HasPrefix(pattern, "|1|") {
+		return matchHashed(pattern, address)
+	}
+	return strings.EqualFold(pattern, address)
+}
+
+// removePatterns 从行中去掉与地址完全相同的主机名，没有剩余主机名时删除整行
+func (f *File) removePatterns(entry Entry, address string) {
+	var keep []string
+	for _, pattern := range entry.Patterns {
+		if !literalPattern(pattern, address) {
+			keep = append(keep, pattern)
+		}
+	}
+	if len(keep) == 0 || entry.Hashed() {
+		f.lines.Delete(entry.Line)
+		return
+	}
+
+	line := strings.Join(keep, ",") + " " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(entry.Key)))
+	if entry.Marker != "" {
+		line = entry.Marker + " " + line
+	}
+	if entry.Comment != "" {
+		line += " " + entry.Comment
+	}
+	f.lines[entry.Line-1] = line
+}
+
+// Replace 用新的主机密钥替换指定主机的普通记录；原有记录已哈希时新记录也会哈希
+func (f *File) Replace(host, port string, keys []ssh.PublicKey) {
+	hashed := false
+	for _, entry := range f.Find(host, port) {
+		if entry.Marker == "" && entry.Hashed() {
+			hashed = true
+		}
+	}
+	f.RemoveHost(host, port)
+	f.Add(host, port, keys, hashed)
+}
+
+// Add 追加主机密钥记录
+func (f *File) Add(host, port string, keys []ssh.PublicKey, hashed bool) {
+	address := Address(host, port)
+	for _, key := range keys {
+		pattern := address
+		if hashed {
+			pattern = xknownhosts.HashHostname(address)
+		}
+		f.lines = append(f.lines, pattern+" "+strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))))
+	}
+	f.reparse()
+}
+
+// reparse 去掉被删除的行并重新解析记录
+func (f *File) reparse() {
+	f.lines.Compact()
+	f.Entries = nil
+	for i, line := range f.lines {
+		if entry, ok := parseLine(line); ok {
+			entry.Line = i + 1
+			f.Entries = append(f.Entries, entry)
+		}
+	}
+}
+
+// Save 写回文件，原内容保留在 known_hosts.old 中，与 ssh-keygen -R 的做法一致
+func (f *File) Save() error {
+	return fileutil.WriteAtomic(f.Path, f.lines.Bytes(), fileutil.Perm(f.Path, 0644), ".old")
+}
+
+// matchPatterns 按 OpenSSH 规则匹配主机名列表：支持 * ? 通配、! 否定以及哈希主机名
+func matchPatterns(patterns []string, address string) bool {
+	host, port := splitAddress(address)
+	matched := false
+	for _, pattern := range patterns {
+		negate := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+
+		var ok bool
+		if strings.HasPrefix(pattern, "|1|") {
+			ok = matchHashed(pattern, address)
+		} else {
+			patternHost, patternPort := splitAddress(pattern)
+			ok = patternPort == port && wildcardMatch(strings.ToLower(patternHost), strings.ToLower(host))
+		}
+		if ok && negate {
+			return false
+		}
+		if ok {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// splitAddress 将 [host]:port 形式拆分为主机和端口，普通主机名的端口为 22
+func splitAddress(address string) (string, string) {
+	if strings.HasPrefix(address, "[") {
+		if host, port, err := net.SplitHostPort(address); err == nil {
+			return host, port
+		}
+	}
+	return address, "22"
+}
+
+// matchHashed 校验 |1|salt|hash 形式的哈希主机名
+func matchHashed(pattern, address string) bool {
+	parts := strings.Split(pattern, "|")
+	if len(parts) != 4 {
+		return false
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	hash, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(address))
+	return hmac.Equal(mac.Sum(nil), hash)
+}
+
+// wildcardMatch 支持 * 和 ? 的通配符匹配
+func wildcardMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := 0; i <= len(s); i++ {
+				if wildcardMatch(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return len(s) == 0
+}
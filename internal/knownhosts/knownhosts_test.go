@@ -0,0 +1,177 @@
+package knownhosts
+
+import (
+	"strings"
+	"testing"
+
+	xknownhosts "golang.org/x/crypto/ssh/knownhosts"
+)
+
+const hostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+
+// parse 从若干行构造 known_hosts 文件
+func parse(lines ...string) *File {
+	f := &File{lines: lines}
+	f.reparse()
+	return f
+}
+
+// text 返回文件当前的内容
+func (f *File) text() string {
+	return string(f.lines.Bytes())
+}
+
+func TestParseLine(t *testing.T) {
+	hashed := xknownhosts.HashHostname("github.com")
+	tests := []struct {
+		line     string
+		ok       bool
+		marker   string
+		patterns string
+		comment  string
+	}{
+		{line: "github.com,140.82.112.3 " + hostKey, ok: true, patterns: "github.com,140.82.112.3"},
+		{line: hashed + " " + hostKey + " 注释", ok: true, patterns: hashed, comment: "注释"},
+		{line: "[git.example.com]:2222 " + hostKey, ok: true, patterns: "[git.example.com]:2222"},
+		{line: "@cert-authority *.example.com " + hostKey, ok: true, marker: MarkerCertAuthority, patterns: "*.example.com"},
+		{line: "@revoked github.com " + hostKey, ok: true, marker: MarkerRevoked, patterns: "github.com"},
+		{line: "# github.com " + hostKey},
+		{line: "   "},
+		{line: "github.com ssh-ed25519"},
+		{line: "github.com ssh-ed25519 不是密钥"},
+	}
+	for _, tt := range tests {
+		entry, ok := parseLine(tt.line)
+		if ok != tt.ok {
+			t.Errorf("parseLine(%q) ok = %v", tt.line, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if entry.Marker != tt.marker || strings.Join(entry.Patterns, ",") != tt.patterns || entry.Comment != tt.comment {
+			t.Errorf("parseLine(%q) = %q %v %q", tt.line, entry.Marker, entry.Patterns, entry.Comment)
+		}
+		if entry.Fingerprint != "SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU" {
+			t.Errorf("指纹 = %s", entry.Fingerprint)
+		}
+	}
+	if entry, _ := parseLine(hashed + " " + hostKey); !entry.Hashed() {
+		t.Errorf("%s 应识别为哈希主机名", hashed)
+	}
+}
+
+func TestMatchPatterns(t *testing.T) {
+	tests := []struct {
+		patterns string
+		address  string
+		want     bool
+	}{
+		{"github.com", "GitHub.com", true},
+		{"gitlab.com,github.com", "github.com", true},
+		{"*.example.com", "git.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"git?.example.com", "git1.example.com", true},
+		{"*.example.com,!secret.example.com", "secret.example.com", false},
+		{"!secret.example.com,*.example.com", "secret.example.com", false},
+		{"!secret.example.com", "git.example.com", false},
+		{"[git.example.com]:2222", "[git.example.com]:2222", true},
+		{"[git.example.com]:2222", "git.example.com", false},
+		{"git.example.com", "[git.example.com]:2222", false},
+		{"[*.example.com]:2222", "[git.example.com]:2222", true},
+		{xknownhosts.HashHostname("[git.example.com]:2222"), "[git.example.com]:2222", true},
+		{xknownhosts.HashHostname("git.example.com"), "[git.example.com]:2222", false},
+	}
+	for _, tt := range tests {
+		if got := matchPatterns(strings.Split(tt.patterns, ","), tt.address); got != tt.want {
+			t.Errorf("matchPatterns(%s, %s) = %v，期望 %v", tt.patterns, tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestMatchHashed(t *testing.T) {
+	hashed := xknownhosts.HashHostname("github.com")
+	if !matchHashed(hashed, "github.com") {
+		t.Errorf("%s 应匹配 github.com", hashed)
+	}
+	if matchHashed(hashed, "gitlab.com") {
+		t.Errorf("%s 不应匹配 gitlab.com", hashed)
+	}
+	for _, pattern := range []string{"|1|abc", "|1|!!!|abc", "|1|c2FsdA==|!!!"} {
+		if matchHashed(pattern, "github.com") {
+			t.Errorf("格式错误的 %s 不应匹配", pattern)
+		}
+	}
+}
+
+func TestRemoveHost(t *testing.T) {
+	hashed := xknownhosts.HashHostname("github.com")
+	f := parse(
+		"# 注释",
+		"github.com,140.82.112.3 "+hostKey+" 共用",
+		hashed+" "+hostKey,
+		"*.github.com "+hostKey,
+		"@revoked github.com "+hostKey,
+		"gitlab.com "+hostKey,
+	)
+	if n := f.RemoveHost("github.com", "22"); n != 2 {
+		t.Errorf("删除了 %d 条记录，期望 2", n)
+	}
+	want := "# 注释\n140.82.112.3 " + hostKey + " 共用\n*.github.com " + hostKey + "\n@revoked github.com " + hostKey + "\ngitlab.com " + hostKey + "\n"
+	if got := f.text(); got != want {
+		t.Errorf("结果:\n%s\n期望:\n%s", got, want)
+	}
+	if len(f.Entries) != 4 || f.Entries[3].Line != 5 {
+		t.Errorf("重新解析后的记录 = %v", f.Entries)
+	}
+}
+
+func TestRemoveEntry(t *testing.T) {
+	const content = "@cert-authority *.example.com " + hostKey + "\n" +
+		"*.example.com,!secret.example.com " + hostKey + "\n" +
+		"git.example.com,mirror.example.com " + hostKey + "\n"
+	tests := []struct {
+		name    string
+		line    int
+		address string
+		removed bool
+		want    string
+	}{
+		{
+			name:    "只通过通配符匹配的 @cert-authority 行",
+			line:    1,
+			address: "git.example.com",
+			want:    content,
+		},
+		{
+			name:    "只通过通配符匹配的普通行",
+			line:    2,
+			address: "git.example.com",
+			want:    content,
+		},
+		{
+			name:    "多主机共用的行只去掉当前主机",
+			line:    3,
+			address: "git.example.com",
+			removed: true,
+			want:    "@cert-authority *.example.com " + hostKey + "\n*.example.com,!secret.example.com " + hostKey + "\nmirror.example.com " + hostKey + "\n",
+		},
+		{
+			name:    "未指定地址时删除整行",
+			line:    2,
+			removed: true,
+			want:    "@cert-authority *.example.com " + hostKey + "\ngit.example.com,mirror.example.com " + hostKey + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parse(strings.Split(strings.TrimSuffix(content, "\n"), "\n")...)
+			if removed := f.RemoveEntry(f.Entries[tt.line-1], tt.address); removed != tt.removed {
+				t.Errorf("RemoveEntry = %v，期望 %v", removed, tt.removed)
+			}
+			if got := f.text(); got != tt.want {
+				t.Errorf("结果:\n%s\n期望:\n%s", got, tt.want)
+			}
+		})
+	}
+}
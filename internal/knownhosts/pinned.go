@@ -0,0 +1,140 @@
+package knownhosts
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// 内置的官方主机密钥目录，来源见各条目的 source 字段
+//
+//go:embed pinned_hosts.json
+var embeddedCatalog []byte
+
+// Provider 表示一个 Git 托管平台公布的主机密钥。
+// 只公布了指纹的平台 Keys 为空，只能用于核对，无法直接写入 known_hosts。
+type Provider struct {
+	Name         string   `json:"name"`
+	Hosts        []string `json:"hosts"`
+	Source       string   `json:"source"`
+	Keys         []string `json:"keys"`
+	Fingerprints []string `json:"fingerprints,omitempty"`
+}
+
+// Catalog 表示官方主机密钥目录
+type Catalog struct {
+	Providers []Provider `json:"providers"`
+}
+
+// CatalogPath 返回用户自定义目录的路径，其中的条目会覆盖同名的内置条目
+func CatalogPath() (string, error) {
+	dir, err := config.AppConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pinned_hosts.json"), nil
+}
+
+// LoadCatalog 读取内置目录，并合并用户自定义目录（存在时）
+func LoadCatalog() (*Catalog, error) {
+	var catalog Catalog
+	if err := json.Unmarshal(embeddedCatalog, &catalog); err != nil {
+		return nil, fmt.Errorf("内置指纹目录损坏: %w", err)
+	}
+
+	path, err := CatalogPath()
+	if err != nil {
+		return &catalog, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &catalog, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("无法读取指纹目录: %w", err)
+	}
+	var user Catalog
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, fmt.Errorf("无法解析指纹目录 %s: %w", path, err)
+	}
+	catalog.merge(user)
+	return &catalog, nil
+}
+
+// merge 合并另一份目录，同名条目以后者为准
+func (c *Catalog) merge(other Catalog) {
+	for _, provider := range other.Providers {
+		replaced := false
+		for i := range c.Providers {
+			if strings.EqualFold(c.Providers[i].Name, provider.Name) {
+				c.Providers[i] = provider
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.Providers = append(c.Providers, provider)
+		}
+	}
+}
+
+// Lookup 查找负责指定主机和端口的平台
+func (c *Catalog) Lookup(host, port string) (Provider, bool) {
+	address := Address(host, port)
+	for _, provider := range c.Providers {
+		for _, h := range provider.Hosts {
+			if strings.EqualFold(h, address) {
+				return provider, true
+			}
+		}
+	}
+	return Provider{}, false
+}
+
+// PublicKeys 解析目录中的完整公钥
+func (p Provider) PublicKeys() ([]ssh.PublicKey, error) {
+	result := make([]ssh.PublicKey, 0, len(p.Keys))
+	for _, line := range p.Keys {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("%s 的主机密钥无法解析: %w", p.Name, err)
+		}
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+// Pinned 判断指纹是否为该平台公布的主机密钥指纹
+func (p Provider) Pinned(fingerprint string) bool {
+	for _, fp := range p.Fingerprints {
+		if fp == fingerprint {
+			return true
+		}
+	}
+	keys, err := p.PublicKeys()
+	if err != nil {
+		return false
+	}
+	for _, key := range keys {
+		if ssh.FingerprintSHA256(key) == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// Mismatches 返回与平台公布指纹不一致的普通记录，@cert-authority 和 @revoked 记录不参与比较
+func (p Provider) Mismatches(entries []Entry) []Entry {
+	var result []Entry
+	for _, entry := range entries {
+		if entry.Marker == "" && !p.Pinned(entry.Fingerprint) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
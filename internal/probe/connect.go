@@ -0,0 +1,319 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/allanpk716/git_ssh_tui/internal/knownhosts"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+)
+
+// StepStatus 表示连接测试中单个步骤的结果
+type StepStatus int
+
+const (
+	StepOK StepStatus = iota
+	StepWarning
+	StepFailed
+)
+
+// Step 表示连接测试中的一个步骤
+type Step struct {
+	Name     string
+	Status   StepStatus
+	Detail   string
+	Duration time.Duration
+}
+
+// Result 汇总一次连接测试的各个步骤
+type Result struct {
+	Steps         []Step
+	ServerVersion string
+	Banner        string
+	Greeting      string
+	Account       string
+}
+
+// OK 判断所有步骤是否都没有失败
+func (r *Result) OK() bool {
+	for _, step := range r.Steps {
+		if step.Status == StepFailed {
+			return false
+		}
+	}
+	return len(r.Steps) > 0
+}
+
+// add 记录一个步骤
+func (r *Result) add(name string, status StepStatus, detail string, start time.Time) {
+	r.Steps = append(r.Steps, Step{Name: name, Status: status, Detail: detail, Duration: time.Since(start)})
+}
+
+// Target 描述连接所需的有效配置，路径均应已展开
+type Target struct {
+	HostName        string
+	Port            string
+	User            string
+	IdentityFile    string
+	CertificateFile string
+	KnownHostsPath  string
+	Agent           sshagent.Agent // 可为 nil
+	Timeout         time.Duration
+}
+
+// Test 执行一次完整的连接测试，认证成功后读取服务器的问候语以识别 Git 平台账户，然后断开
+func Test(ctx context.Context, t Target) *Result {
+	client, result := Connect(ctx, t)
+	if client == nil {
+		return result
+	}
+	defer client.Close()
+
+	start := time.Now()
+	timeout := t.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	if greeting, err := Greeting(ctx, client, timeout); err == nil {
+		result.Greeting = greeting
+		result.Account = ParseAccount(greeting)
+	}
+	if result.Account != "" {
+		result.add("账户", StepOK, result.Account, start)
+	}
+	return result
+}
+
+// Connect 依次解析域名、建立 TCP 连接、校验主机密钥并进行公钥认证，每一步的结果记录在 Result 中。
+// 成功时返回已认证的客户端，由调用方关闭。
+func Connect(ctx context.Context, t Target) (*ssh.Client, *Result) {
+	result := &Result{}
+	if t.Timeout == 0 {
+		t.Timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	// DNS
+	start := time.Now()
+	if net.ParseIP(t.HostName) != nil {
+		result.add("DNS", StepOK, "HostName 为 IP 地址，无需解析", start)
+	} else {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, t.HostName)
+		if err != nil {
+			result.add("DNS", StepFailed, Explain(err), start)
+			return nil, result
+		}
+		result.add("DNS", StepOK, strings.Join(addrs, ", "), start)
+	}
+
+	// TCP
+	start = time.Now()
+	address := net.JoinHostPort(t.HostName, t.Port)
+	conn, err := dial(ctx, address, t.Timeout)
+	if err != nil {
+		result.add("TCP 连接", StepFailed, Explain(err), start)
+		return nil, result
+	}
+	result.add("TCP 连接", StepOK, "已连接 "+conn.RemoteAddr().String(), start)
+	conn.SetDeadline(time.Now().Add(t.Timeout))
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// 主机密钥与认证在同一次握手中完成
+	auth, authDetail, err := authMethod(t)
+	checker, err2 := newHostKeyChecker(t)
+	if err == nil {
+		err = err2
+	}
+	if err != nil {
+		conn.Close()
+		result.add("准备认证", StepFailed, err.Error(), time.Now())
+		return nil, result
+	}
+
+	config := &ssh.ClientConfig{
+		User:              t.User,
+		Auth:              []ssh.AuthMethod{auth},
+		HostKeyCallback:   checker.callback,
+		HostKeyAlgorithms: checker.algorithms(),
+		BannerCallback: func(message string) error {
+			result.Banner += message
+			return nil
+		},
+		Timeout: t.Timeout,
+	}
+	start = time.Now()
+	checker.start = start
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+	if checker.step != nil {
+		result.Steps = append(result.Steps, *checker.step)
+	}
+	if err != nil {
+		conn.Close()
+		if checker.step == nil || checker.step.Status != StepFailed {
+			result.add("认证", StepFailed, Explain(err), start)
+		}
+		return nil, result
+	}
+	result.ServerVersion = string(sshConn.ServerVersion())
+	result.add("认证", StepOK, fmt.Sprintf("已认证为 %s（%s）", t.User, authDetail), start)
+	conn.SetDeadline(time.Time{})
+	return ssh.NewClient(sshConn, chans, reqs), result
+}
+
+// authMethod 按 IdentitiesOnly 的语义准备认证方式：配置了 IdentityFile 时只使用该密钥（及其证书），
+// 私钥已加密时从 ssh-agent 中取出对应的身份；未配置时使用 agent 中的全部身份
+func authMethod(t Target) (ssh.AuthMethod, string, error) {
+	if t.IdentityFile == "" {
+		if t.Agent == nil {
+			return nil, "", fmt.Errorf("未配置 IdentityFile，且无法连接 ssh-agent")
+		}
+		return ssh.PublicKeysCallback(t.Agent.Signers), "使用 ssh-agent 中的全部身份", nil
+	}
+
+	info, err := keys.Inspect(t.IdentityFile)
+	if err != nil {
+		return nil, "", err
+	}
+	certPath := t.CertificateFile
+	if certPath == "" {
+		certPath = keys.CertPathFor(t.IdentityFile)
+	}
+
+	if info.Encrypted {
+		if t.Agent == nil {
+			return nil, "", fmt.Errorf("私钥已加密，且无法连接 ssh-agent，请先将密钥加入 agent")
+		}
+		signers, err := t.Agent.Signers()
+		if err != nil {
+			return nil, "", fmt.Errorf("无法读取 agent 身份: %w", err)
+		}
+		var matched []ssh.Signer
+		for _, signer := range signers {
+			pub := signer.PublicKey()
+			if cert, ok := pub.(*ssh.Certificate); ok {
+				pub = cert.Key
+			}
+			if ssh.FingerprintSHA256(pub) == info.Fingerprint {
+				matched = append(matched, signer)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, "", fmt.Errorf("私钥已加密且未加入 ssh-agent，请先在 ssh-agent 面板中添加")
+		}
+		return ssh.PublicKeys(matched...), fmt.Sprintf("%s %s，来自 ssh-agent", keys.KeyTypeName(info.Type), info.Fingerprint), nil
+	}
+
+	data, err := os.ReadFile(t.IdentityFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("无法读取私钥: %w", err)
+	}
+	key, err := keys.ParsePrivateKey(data, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("无法创建签名器: %w", err)
+	}
+	signers := []ssh.Signer{signer}
+	detail := fmt.Sprintf("%s %s", keys.KeyTypeName(info.Type), info.Fingerprint)
+
+	// 与 OpenSSH 一样优先使用证书
+	if cert, err := keys.ParseCertificate(certPath); err == nil {
+		if certSigner, err := ssh.NewCertSigner(cert.Cert, signer); err == nil {
+			signers = append([]ssh.Signer{certSigner}, signers...)
+			detail += "，附带证书"
+		}
+	}
+	return ssh.PublicKeys(signers...), detail, nil
+}
+
+// hostKeyChecker 按 known_hosts 校验主机密钥，并记录校验结果
+type hostKeyChecker struct {
+	entries []knownhosts.Entry
+	step    *Step
+	start   time.Time
+}
+
+// newHostKeyChecker 读取 known_hosts 中适用于目标地址的记录
+func newHostKeyChecker(t Target) (*hostKeyChecker, error) {
+	c := &hostKeyChecker{start: time.Now()}
+	if t.KnownHostsPath == "" {
+		return c, nil
+	}
+	file, err := knownhosts.Load(t.KnownHostsPath)
+	if err != nil {
+		return nil, err
+	}
+	c.entries = file.Find(t.HostName, t.Port)
+	return c, nil
+}
+
+// algorithms 与 OpenSSH 一样优先协商 known_hosts 中已有的密钥类型，避免误报密钥不一致；没有记录时使用默认顺序
+func (c *hostKeyChecker) algorithms() []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, entry := range c.entries {
+		if entry.Marker != "" {
+			continue
+		}
+		algorithms := []string{entry.Key.Type()}
+		if entry.Key.Type() == ssh.KeyAlgoRSA {
+			algorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		}
+		for _, algorithm := range algorithms {
+			if !seen[algorithm] {
+				seen[algorithm] = true
+				result = append(result, algorithm)
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	for _, algorithm := range append(HostKeyAlgorithms, ssh.KeyAlgoRSASHA256) {
+		if !seen[algorithm] {
+			result = append(result, algorithm)
+		}
+	}
+	return result
+}
+
+// callback 实现 ssh.HostKeyCallback。未知主机只给出警告并继续测试，密钥不一致或已吊销时中止连接
+func (c *hostKeyChecker) callback(_ string, _ net.Addr, key ssh.PublicKey) error {
+	fingerprint := ssh.FingerprintSHA256(key)
+	known := false
+	for _, entry := range c.entries {
+		switch {
+		case entry.Marker == knownhosts.MarkerRevoked && entry.Fingerprint == fingerprint:
+			c.record(StepFailed, fmt.Sprintf("主机密钥 %s 已在 known_hosts 中标记为吊销", fingerprint))
+			return errors.New("host key revoked")
+		case entry.Marker == "":
+			known = true
+			if entry.Fingerprint == fingerprint {
+				c.record(StepOK, fmt.Sprintf("与 known_hosts 第 %d 行一致（%s %s）", entry.Line, keys.KeyTypeName(key.Type()), fingerprint))
+				return nil
+			}
+		}
+	}
+	if known {
+		c.record(StepFailed, fmt.Sprintf("服务器的主机密钥 %s 与 known_hosts 中的记录不一致，可能遭遇了中间人攻击，也可能是服务器更换了密钥", fingerprint))
+		return errors.New("host key mismatch")
+	}
+	c.record(StepWarning, fmt.Sprintf("known_hosts 中没有该主机的记录（%s %s），ssh 首次连接时会要求确认", keys.KeyTypeName(key.Type()), fingerprint))
+	return nil
+}
+
+// record 记录主机密钥校验步骤
+func (c *hostKeyChecker) record(status StepStatus, detail string) {
+	c.step = &Step{Name: "主机密钥", Status: status, Detail: detail, Duration: time.Since(c.start)}
+}
@@ -0,0 +1,313 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/allanpk716/git_ssh_tui/internal/knownhosts"
+	"golang.org/x/crypto/ssh"
+)
+
+const testGreeting = "Hi octocat! You've successfully authenticated, but GitHub does not provide shell access."
+
+// newHostKey 生成服务器使用的主机密钥
+func newHostKey(t *testing.T, rsaKey bool) ssh.Signer {
+	t.Helper()
+	var key interface{}
+	var err error
+	if rsaKey {
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	} else {
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// newIdentity 生成客户端私钥文件
+func newIdentity(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	pub, err := keys.Generate(path, keys.GenerateEd25519, "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, pub
+}
+
+// execHandler 处理 exec 请求并返回退出状态
+type execHandler func(command string, channel ssh.Channel) uint32
+
+// startServer 启动进程内的 SSH 服务器：只接受 authorized 公钥，shell 请求时像 Git 平台一样输出问候语后退出，
+// exec 请求交给 exec 处理
+func startServer(t *testing.T, authorized ssh.PublicKey, exec execHandler, hostKeys ...ssh.Signer) (string, string) {
+	t.Helper()
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if authorized != nil && bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized")
+		},
+		BannerCallback: func(ssh.ConnMetadata) string { return "welcome\n" },
+	}
+	for _, key := range hostKeys {
+		config.AddHostKey(key)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveConn(conn, config, exec)
+		}
+	}()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port
+}
+
+func serveConn(conn net.Conn, config *ssh.ServerConfig, exec execHandler) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer channel.Close()
+			for req := range requests {
+				var status uint32
+				switch {
+				case req.Type == "shell":
+					req.Reply(true, nil)
+					io.WriteString(channel.Stderr(), testGreeting+"\n")
+					status = 1
+				case req.Type == "exec" && exec != nil:
+					var payload struct{ Command string }
+					if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+						req.Reply(false, nil)
+						continue
+					}
+					req.Reply(true, nil)
+					status = exec(payload.Command, channel)
+				default:
+					req.Reply(false, nil)
+					continue
+				}
+				channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+				return
+			}
+		}()
+	}
+}
+
+// writeKnownHosts 生成只包含指定主机密钥的 known_hosts
+func writeKnownHosts(t *testing.T, host, port string, keys ...ssh.PublicKey) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	file, err := knownhosts.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Add(host, port, keys, false)
+	if err := file.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// findStep 返回指定名称的步骤
+func findStep(t *testing.T, result *Result, name string) Step {
+	t.Helper()
+	for _, step := range result.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	t.Fatalf("缺少步骤 %q: %+v", name, result.Steps)
+	return Step{}
+}
+
+func TestTestSuccess(t *testing.T) {
+	hostKey := newHostKey(t, false)
+	identity, pub := newIdentity(t)
+	host, port := startServer(t, pub, nil, hostKey)
+
+	result := Test(context.Background(), Target{
+		HostName:       host,
+		Port:           port,
+		User:           "git",
+		IdentityFile:   identity,
+		KnownHostsPath: writeKnownHosts(t, host, port, hostKey.PublicKey()),
+		Timeout:        5 * time.Second,
+	})
+	if !result.OK() {
+		t.Fatalf("连接测试失败: %+v", result.Steps)
+	}
+	if step := findStep(t, result, "主机密钥"); step.Status != StepOK {
+		t.Errorf("主机密钥步骤 = %+v", step)
+	}
+	if step := findStep(t, result, "认证"); step.Status != StepOK {
+		t.Errorf("认证步骤 = %+v", step)
+	}
+	if result.Account != "octocat" || result.Greeting != testGreeting {
+		t.Errorf("账户 = %q，问候语 = %q", result.Account, result.Greeting)
+	}
+	if result.Banner != "welcome\n" {
+		t.Errorf("Banner = %q", result.Banner)
+	}
+	if !strings.HasPrefix(result.ServerVersion, "SSH-2.0-") {
+		t.Errorf("ServerVersion = %q", result.ServerVersion)
+	}
+}
+
+func TestConnectUnknownHost(t *testing.T) {
+	hostKey := newHostKey(t, false)
+	identity, pub := newIdentity(t)
+	host, port := startServer(t, pub, nil, hostKey)
+
+	client, result := Connect(context.Background(), Target{
+		HostName:       host,
+		Port:           port,
+		User:           "git",
+		IdentityFile:   identity,
+		KnownHostsPath: filepath.Join(t.TempDir(), "known_hosts"),
+		Timeout:        5 * time.Second,
+	})
+	if client == nil {
+		t.Fatalf("未知主机应只给出警告: %+v", result.Steps)
+	}
+	client.Close()
+	if step := findStep(t, result, "主机密钥"); step.Status != StepWarning {
+		t.Errorf("主机密钥步骤 = %+v", step)
+	}
+}
+
+func TestConnectAuthFailure(t *testing.T) {
+	hostKey := newHostKey(t, false)
+	_, authorized := newIdentity(t)
+	identity, _ := newIdentity(t)
+	host, port := startServer(t, authorized, nil, hostKey)
+
+	client, result := Connect(context.Background(), Target{
+		HostName:       host,
+		Port:           port,
+		User:           "git",
+		IdentityFile:   identity,
+		KnownHostsPath: writeKnownHosts(t, host, port, hostKey.PublicKey()),
+		Timeout:        5 * time.Second,
+	})
+	if client != nil {
+		client.Close()
+		t.Fatal("未授权的密钥不应认证成功")
+	}
+	if result.OK() {
+		t.Error("认证失败时 OK 应为 false")
+	}
+	if step := findStep(t, result, "主机密钥"); step.Status != StepOK {
+		t.Errorf("主机密钥步骤 = %+v", step)
+	}
+	step := findStep(t, result, "认证")
+	if step.Status != StepFailed || !strings.HasPrefix(step.Detail, "认证失败") {
+		t.Errorf("认证步骤 = %+v", step)
+	}
+}
+
+func TestConnectHostKeyMismatch(t *testing.T) {
+	hostKey := newHostKey(t, false)
+	identity, pub := newIdentity(t)
+	host, port := startServer(t, pub, nil, hostKey)
+
+	result := Test(context.Background(), Target{
+		HostName:       host,
+		Port:           port,
+		User:           "git",
+		IdentityFile:   identity,
+		KnownHostsPath: writeKnownHosts(t, host, port, newHostKey(t, false).PublicKey()),
+		Timeout:        5 * time.Second,
+	})
+	if result.OK() {
+		t.Fatal("主机密钥不一致时应失败")
+	}
+	step := result.Steps[len(result.Steps)-1]
+	if step.Name != "主机密钥" || step.Status != StepFailed || !strings.Contains(step.Detail, "不一致") {
+		t.Errorf("最后一步 = %+v", step)
+	}
+	for _, step := range result.Steps {
+		if step.Name == "认证" {
+			t.Errorf("主机密钥不一致时不应再记录认证步骤: %+v", step)
+		}
+	}
+	if result.Account != "" {
+		t.Errorf("不应读取问候语: %q", result.Account)
+	}
+}
+
+func TestFetchHostKeys(t *testing.T) {
+	ed25519Key := newHostKey(t, false)
+	rsaKey := newHostKey(t, true)
+	host, port := startServer(t, nil, nil, ed25519Key, rsaKey)
+
+	found, err := FetchHostKeys(context.Background(), net.JoinHostPort(host, port), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{
+		ssh.FingerprintSHA256(ed25519Key.PublicKey()): true,
+		ssh.FingerprintSHA256(rsaKey.PublicKey()):     true,
+	}
+	if len(found) != len(want) {
+		t.Fatalf("获取到 %d 个主机密钥，期望 %d", len(found), len(want))
+	}
+	for _, key := range found {
+		if !want[ssh.FingerprintSHA256(key)] {
+			t.Errorf("意外的主机密钥 %s %s", key.Type(), ssh.FingerprintSHA256(key))
+		}
+	}
+}
+
+func TestFetchHostKeysRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	if _, err := FetchHostKeys(context.Background(), address, 2*time.Second); err == nil {
+		t.Fatal("端口未监听时应返回错误")
+	}
+}
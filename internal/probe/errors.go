@@ -0,0 +1,54 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// Explain 将网络和 SSH 错误转换为便于理解的原因说明
+func Explain(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return "域名无法解析，请检查 HostName 是否拼写正确"
+		}
+		if dnsErr.IsTimeout {
+			return "DNS 查询超时，请检查网络或 DNS 设置"
+		}
+		return "DNS 查询失败: " + dnsErr.Err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "已取消"
+	case errors.Is(err, context.DeadlineExceeded), isTimeout(err):
+		return "连接超时，可能被防火墙拦截；部分平台支持通过 443 端口连接"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "连接被拒绝，请检查 Port 是否正确、SSH 服务是否在运行"
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return "网络不可达，请检查网络连接或代理设置"
+	case errors.Is(err, syscall.ECONNRESET):
+		return "连接被重置，可能被防火墙或代理中断"
+	case strings.Contains(msg, "unable to authenticate"):
+		return "认证失败：服务器不接受提供的密钥，请确认公钥已添加到账户，且 User 正确（Git 平台通常为 git）"
+	case strings.Contains(msg, "no common algorithm"):
+		return "与服务器没有共同支持的算法: " + msg
+	case strings.Contains(msg, "EOF"):
+		return "服务器在握手阶段断开了连接，该端口可能不是 SSH 服务，或连接受到限制"
+	}
+	return msg
+}
+
+// isTimeout 判断是否为网络超时错误
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,166 @@
+package probe
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// RepoInfo 表示 git-upload-pack 公布的仓库信息
+type RepoInfo struct {
+	DefaultBranch string
+	Head          string
+	Refs          int
+	Branches      int
+	Tags          int
+}
+
+// LsRemote 与 git ls-remote 一样在已认证的连接上执行 git-upload-pack，读取引用公告后立即结束，不会下载任何对象。
+// 服务器拒绝访问时返回其输出的原始错误信息。
+func LsRemote(ctx context.Context, client *ssh.Client, path string, timeout time.Duration) (*RepoInfo, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("无法打开会话: %w", err)
+	}
+	defer session.Close()
+
+	var stderr lockedBuffer
+	session.Stderr = &stderr
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Start("git-upload-pack " + shellQuote(path)); err != nil {
+		return nil, fmt.Errorf("无法执行 git-upload-pack: %w", err)
+	}
+
+	type result struct {
+		info *RepoInfo
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		info, err := readAdvertisement(bufio.NewReader(stdout))
+		done <- result{info, err}
+	}()
+
+	var r result
+	select {
+	case r = <-done:
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("等待 git-upload-pack 响应超时")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// 发送 flush 包告诉服务器不需要任何对象
+	io.WriteString(stdin, "0000")
+	stdin.Close()
+
+	if r.err != nil {
+		// 等待会话结束，确保服务器写到 stderr 的错误信息已全部读取
+		waited := make(chan struct{})
+		go func() {
+			session.Wait()
+			close(waited)
+		}()
+		select {
+		case <-waited:
+		case <-time.After(2 * time.Second):
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, r.err
+	}
+	return r.info, nil
+}
+
+// readAdvertisement 读取 pkt-line 格式的引用公告（协议 v0），直到 flush 包
+func readAdvertisement(r *bufio.Reader) (*RepoInfo, error) {
+	info := &RepoInfo{}
+	first := true
+	for {
+		line, flush, err := readPktLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if flush {
+			return info, nil
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if msg, ok := strings.CutPrefix(line, "ERR "); ok {
+			return nil, errors.New(msg)
+		}
+
+		if first {
+			first = false
+			var capabilities string
+			line, capabilities, _ = strings.Cut(line, "\x00")
+			for _, capability := range strings.Fields(capabilities) {
+				if target, ok := strings.CutPrefix(capability, "symref=HEAD:"); ok {
+					info.DefaultBranch = strings.TrimPrefix(target, "refs/heads/")
+				}
+			}
+		}
+
+		hash, ref, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		switch {
+		case ref == "capabilities^{}":
+			// 空仓库只公布能力列表
+		case ref == "HEAD":
+			info.Head = hash
+		case strings.HasSuffix(ref, "^{}"):
+			// 附注标签解引用后的提交，不单独计数
+		default:
+			info.Refs++
+			if strings.HasPrefix(ref, "refs/heads/") {
+				info.Branches++
+			} else if strings.HasPrefix(ref, "refs/tags/") {
+				info.Tags++
+			}
+		}
+	}
+}
+
+// readPktLine 读取一个 pkt-line，返回内容以及是否为 flush 包
+func readPktLine(r *bufio.Reader) (string, bool, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return "", false, fmt.Errorf("服务器没有返回引用列表: %w", err)
+	}
+	length, err := strconv.ParseUint(string(header[:]), 16, 16)
+	if err != nil {
+		return "", false, fmt.Errorf("无效的 pkt-line 长度: %q", header)
+	}
+	if length == 0 {
+		return "", true, nil
+	}
+	if length < 4 {
+		return "", false, fmt.Errorf("无效的 pkt-line 长度: %q", header)
+	}
+	data := make([]byte, length-4)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return "", false, fmt.Errorf("读取引用列表失败: %w", err)
+	}
+	return string(data), false, nil
+}
+
+// shellQuote 按 git 的方式用单引号包裹路径
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
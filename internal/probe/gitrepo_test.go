@@ -0,0 +1,258 @@
+package probe
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// pkt 按 pkt-line 格式编码一行
+func pkt(line string) string {
+	return fmt.Sprintf("%04x%s", len(line)+4, line)
+}
+
+func TestReadAdvertisement(t *testing.T) {
+	const (
+		main = "1111111111111111111111111111111111111111"
+		dev  = "2222222222222222222222222222222222222222"
+		tag  = "3333333333333333333333333333333333333333"
+	)
+	tests := []struct {
+		name string
+		data string
+		want RepoInfo
+		err  string
+	}{
+		{
+			name: "引用",
+			data: pkt(main+" HEAD\x00multi_ack symref=HEAD:refs/heads/main agent=git/2.39\n") +
+				pkt(dev+" refs/heads/dev\n") +
+				pkt(main+" refs/heads/main\n") +
+				pkt(tag+" refs/tags/v1\n") +
+				pkt(main+" refs/tags/v1^{}\n") +
+				pkt(main+" refs/pull/1/head\n") +
+				"0000",
+			want: RepoInfo{DefaultBranch: "main", Head: main, Refs: 4, Branches: 2, Tags: 1},
+		},
+		{
+			name: "空仓库",
+			data: pkt(strings.Repeat("0", 40)+" capabilities^{}\x00multi_ack agent=git/2.39\n") + "0000",
+			want: RepoInfo{},
+		},
+		{
+			name: "服务器错误",
+			data: pkt("ERR Repository not found.\n"),
+			err:  "Repository not found.",
+		},
+		{
+			name: "提前结束",
+			data: pkt(main + " HEAD\n"),
+			err:  "服务器没有返回引用列表",
+		},
+		{
+			name: "无效长度",
+			data: "zzzz",
+			err:  "无效的 pkt-line 长度",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := readAdvertisement(bufio.NewReader(strings.NewReader(tt.data)))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("错误 = %v，期望包含 %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *info != tt.want {
+				t.Errorf("RepoInfo = %+v，期望 %+v", *info, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's/repo.git"); got != `'it'\''s/repo.git'` {
+		t.Errorf("shellQuote = %s", got)
+	}
+}
+
+// uploadPack 在本机执行 exec 请求中的 git-upload-pack
+func uploadPack(command string, channel ssh.Channel) uint32 {
+	path, ok := strings.CutPrefix(command, "git-upload-pack ")
+	if !ok {
+		return 127
+	}
+	cmd := exec.Command("git-upload-pack", strings.Trim(path, "'"))
+	cmd.Stdout = channel
+	cmd.Stderr = channel.Stderr()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return 1
+	}
+	if err := cmd.Start(); err != nil {
+		return 127
+	}
+	// 不等待标准输入复制结束，进程退出后立即结束输出
+	go func() {
+		io.Copy(stdin, channel)
+		stdin.Close()
+	}()
+	err = cmd.Wait()
+	channel.CloseWrite()
+	if err != nil {
+		return 128
+	}
+	return 0
+}
+
+// git 在 dir 中执行 git 命令
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// connectGit 连接只接受 exec 请求的测试服务器，返回已认证的客户端
+func connectGit(t *testing.T, handler execHandler) *ssh.Client {
+	t.Helper()
+	hostKey := newHostKey(t, false)
+	identity, pub := newIdentity(t)
+	host, port := startServer(t, pub, handler, hostKey)
+	client, result := Connect(context.Background(), Target{
+		HostName:       host,
+		Port:           port,
+		User:           "git",
+		IdentityFile:   identity,
+		KnownHostsPath: writeKnownHosts(t, host, port, hostKey.PublicKey()),
+		Timeout:        5 * time.Second,
+	})
+	if client == nil {
+		t.Fatalf("无法连接测试服务器: %+v", result.Steps)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestLsRemote(t *testing.T) {
+	if _, err := exec.LookPath("git-upload-pack"); err != nil {
+		t.Skip("未安装 git")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	bare := filepath.Join(home, "repo.git")
+	empty := filepath.Join(home, "empty.git")
+	work := filepath.Join(home, "work")
+	git(t, home, "init", "-q", "--bare", "-b", "main", bare)
+	git(t, home, "init", "-q", "--bare", "-b", "main", empty)
+	git(t, home, "init", "-q", "-b", "main", work)
+	git(t, work, "commit", "-q", "--allow-empty", "-m", "init")
+	git(t, work, "branch", "dev")
+	git(t, work, "tag", "-a", "v1", "-m", "v1")
+	git(t, work, "push", "-q", bare, "main", "dev", "v1")
+	head := git(t, work, "rev-parse", "HEAD")
+
+	client := connectGit(t, uploadPack)
+	ctx := context.Background()
+
+	info, err := LsRemote(ctx, client, bare, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Head != head || info.Refs != 3 || info.Branches != 2 || info.Tags != 1 {
+		t.Errorf("RepoInfo = %+v", *info)
+	}
+	if info.DefaultBranch != "main" {
+		t.Errorf("DefaultBranch = %q", info.DefaultBranch)
+	}
+
+	info, err = LsRemote(ctx, client, empty, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Head != "" || info.Refs != 0 {
+		t.Errorf("空仓库 RepoInfo = %+v", *info)
+	}
+
+	_, err = LsRemote(ctx, client, filepath.Join(home, "missing.git"), 5*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "does not appear to be a git repository") {
+		t.Errorf("不存在的仓库返回 %v", err)
+	}
+}
+
+func TestLsRemoteNotFound(t *testing.T) {
+	// 与 GitHub 一样只在 stderr 输出错误，不返回引用公告
+	client := connectGit(t, func(_ string, channel ssh.Channel) uint32 {
+		io.WriteString(channel.Stderr(), "ERROR: Repository not found.\n")
+		channel.CloseWrite()
+		return 1
+	})
+	_, err := LsRemote(context.Background(), client, "octocat/missing.git", 5*time.Second)
+	if err == nil || err.Error() != "ERROR: Repository not found." {
+		t.Errorf("错误 = %v", err)
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	hostKey := newHostKey(t, false)
+	identity, pub := newIdentity(t)
+	host, port := startServer(t, pub, nil, hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, closedPort, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	knownHosts := writeKnownHosts(t, host, port, hostKey.PublicKey())
+	targets := map[string]Target{
+		"ok":   {HostName: host, Port: port, User: "git", IdentityFile: identity, KnownHostsPath: knownHosts},
+		"down": {HostName: host, Port: closedPort, User: "git", IdentityFile: identity, KnownHostsPath: knownHosts},
+	}
+	results := map[string]Health{}
+	for result := range CheckAll(context.Background(), targets, 2) {
+		results[result.Name] = result.Health
+	}
+	if len(results) != 2 {
+		t.Fatalf("结果数量 = %d，期望 2", len(results))
+	}
+	if h := results["ok"]; h.Status != StepOK || h.Account != "octocat" {
+		t.Errorf("ok = %+v", h)
+	}
+	if h := results["down"]; h.Status != StepFailed || h.Summary != "TCP 连接失败" {
+		t.Errorf("down = %+v", h)
+	}
+}
+
+func TestCheckAllCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// 取消后通道应关闭，不会阻塞
+	for result := range CheckAll(ctx, map[string]Target{"a": {HostName: "127.0.0.1", Port: "1"}}, 1) {
+		t.Errorf("取消后不应返回结果: %+v", result)
+	}
+}
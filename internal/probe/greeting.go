@@ -0,0 +1,83 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// greetingPatterns 各 Git 平台认证成功后问候语中账户名的位置
+var greetingPatterns = []*regexp.Regexp{
+	// GitHub: Hi octocat! You've successfully authenticated, but GitHub does not provide shell access.
+	regexp.MustCompile(`Hi ([^\s!]+)! You've successfully authenticated`),
+	// Gitea/Forgejo: Hi there, octocat! You've successfully authenticated with the key named ...
+	regexp.MustCompile(`Hi there,? ([^\s!]+)! You've successfully authenticated`),
+	// GitLab: Welcome to GitLab, @octocat!
+	regexp.MustCompile(`Welcome to GitLab, @([^\s!]+)!`),
+	// Bitbucket: authenticated via ssh key. ... logged in as octocat.
+	regexp.MustCompile(`logged in as ([^\s.]+)`),
+}
+
+// ParseAccount 从 Git 平台的问候语中解析出认证的账户名，无法识别时返回空字符串。
+// 使用部署密钥时 GitHub 返回的是 owner/repo 形式。
+func ParseAccount(greeting string) string {
+	for _, pattern := range greetingPatterns {
+		if match := pattern.FindStringSubmatch(greeting); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// Greeting 与 ssh -T 一样请求一个不带终端的 shell 并读取服务器输出。
+// Git 平台会打印问候语后断开；普通服务器的 shell 会因标准输入结束而退出，最多等待 timeout。
+func Greeting(ctx context.Context, client *ssh.Client, timeout time.Duration) (string, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+
+	var out lockedBuffer
+	session.Stdout = &out
+	session.Stderr = &out
+	if err := session.Shell(); err != nil {
+		return "", err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		// 平台通常以非零状态退出，这里只关心输出
+		session.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	case <-ctx.Done():
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// lockedBuffer 供 stdout 和 stderr 同时写入的缓冲区
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,94 @@
+package probe
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 健康检查的默认参数
+const (
+	HealthWorkers = 8
+	HealthTimeout = 5 * time.Second
+)
+
+// Health 表示一个主机的健康检查结果
+type Health struct {
+	Status  StepStatus
+	Latency time.Duration
+	Summary string
+	Account string
+	Checked time.Time
+}
+
+// HealthResult 关联健康检查结果与主机名
+type HealthResult struct {
+	Name   string
+	Health Health
+}
+
+// Summarize 将连接测试结果汇总为健康状态，延迟取 TCP 连接耗时
+func Summarize(r *Result) Health {
+	h := Health{Status: StepOK, Account: r.Account, Checked: time.Now()}
+	for _, step := range r.Steps {
+		if step.Name == "TCP 连接" && step.Status == StepOK {
+			h.Latency = step.Duration
+		}
+		if step.Status > h.Status {
+			h.Status = step.Status
+			h.Summary = step.Name
+		}
+	}
+	switch h.Status {
+	case StepWarning:
+		h.Summary += "需确认"
+	case StepFailed:
+		h.Summary += "失败"
+	}
+	return h
+}
+
+// CheckAll 使用固定数量的 worker 并发检查所有目标，每完成一个就发送到返回的通道，全部完成或 ctx 取消后关闭通道
+func CheckAll(ctx context.Context, targets map[string]Target, workers int) <-chan HealthResult {
+	jobs := make(chan string)
+	results := make(chan HealthResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				target := targets[name]
+				if target.Timeout == 0 {
+					target.Timeout = HealthTimeout
+				}
+				health := Summarize(Test(ctx, target))
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case results <- HealthResult{Name: name, Health: health}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for name := range targets {
+			select {
+			case jobs <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
@@ -0,0 +1,90 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// DefaultTimeout 单次连接的默认超时时间
+const DefaultTimeout = 10 * time.Second
+
+// HostKeyAlgorithms 获取主机密钥时逐一请求的算法，rsa-sha2-512 返回的是 ssh-rsa 密钥
+var HostKeyAlgorithms = []string{
+	ssh.KeyAlgoED25519,
+	ssh.KeyAlgoECDSA256,
+	ssh.KeyAlgoECDSA384,
+	ssh.KeyAlgoECDSA521,
+	ssh.KeyAlgoRSASHA512,
+}
+
+// errHostKeyCaptured 拿到主机密钥后中止握手
+var errHostKeyCaptured = errors.New("host key captured")
+
+// FetchHostKeys 与 ssh-keyscan 类似，对每种算法各握手一次以收集服务器的全部主机密钥。
+// 只进行到密钥交换，不会尝试认证。address 为 host:port 形式。
+func FetchHostKeys(ctx context.Context, address string, timeout time.Duration) ([]ssh.PublicKey, error) {
+	var result []ssh.PublicKey
+	var lastErr error
+	seen := map[string]bool{}
+	for _, algorithm := range HostKeyAlgorithms {
+		conn, err := dial(ctx, address, timeout)
+		if err != nil {
+			return nil, err
+		}
+		key, err := handshakeHostKey(ctx, conn, address, algorithm, timeout)
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			// 服务器不支持该算法时继续尝试下一个
+			lastErr = err
+			continue
+		}
+		if fingerprint := ssh.FingerprintSHA256(key); !seen[fingerprint] {
+			seen[fingerprint] = true
+			result = append(result, key)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("未能获取 %s 的主机密钥: %w", address, lastErr)
+	}
+	return result, nil
+}
+
+// dial 建立 TCP 连接
+func dial(ctx context.Context, address string, timeout time.Duration) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("无法连接 %s: %w", address, err)
+	}
+	return conn, nil
+}
+
+// handshakeHostKey 只允许指定的主机密钥算法进行握手，并返回服务器出示的主机密钥
+func handshakeHostKey(ctx context.Context, conn net.Conn, address, algorithm string, timeout time.Duration) (ssh.PublicKey, error) {
+	conn.SetDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	var captured ssh.PublicKey
+	config := &ssh.ClientConfig{
+		HostKeyAlgorithms: []string{algorithm},
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			captured = key
+			return errHostKeyCaptured
+		},
+		Timeout: timeout,
+	}
+	_, _, _, err := ssh.NewClientConn(conn, address, config)
+	if captured != nil {
+		return captured, nil
+	}
+	return nil, err
+}
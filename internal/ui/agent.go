@@ -0,0 +1,349 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/agent"
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+)
+
+// agentMode 表示 Agent 视图当前的操作
+type agentMode int
+
+const (
+	agentModeList agentMode = iota
+	agentModeAdd
+	agentModeLock
+	agentModeUnlock
+	agentModeConfirmRemove
+	agentModeConfirmRemoveAll
+)
+
+// AgentItem 实现 list.Item 接口，表示 agent 中的一个身份
+type AgentItem struct {
+	identity agent.Identity
+	hosts    []string
+}
+
+func (a AgentItem) FilterValue() string {
+	return a.identity.Comment
+}
+
+func (a AgentItem) Title() string {
+	if a.identity.Comment == "" {
+		return a.identity.Fingerprint
+	}
+	return a.identity.Comment
+}
+
+func (a AgentItem) Description() string {
+	desc := fmt.Sprintf("%s %s", keys.KeyTypeName(a.identity.Type), a.identity.Fingerprint)
+	if len(a.hosts) > 0 {
+		desc += " • 用于: " + strings.Join(a.hosts, ", ")
+	}
+	return desc
+}
+
+// agentState 保存 Agent 视图的状态
+type agentState struct {
+	mode      agentMode
+	form      inputForm
+	unloaded  []string
+	available bool
+}
+
+// newAgentList 创建 agent 身份列表
+func newAgentList() list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "ssh-agent"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+	return l
+}
+
+// withAgent 连接 ssh-agent 并执行操作
+func withAgent(fn func(c *agent.Client) error) error {
+	client, err := agent.Dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return fn(client)
+}
+
+// hostFingerprints 返回每个主机 IdentityFile 对应公钥的指纹
+func (m Model) hostFingerprints() map[string]string {
+	result := map[string]string{}
+	for _, host := range m.sshConfig.GetHosts() {
+		if host.IdentityFile == "" {
+			continue
+		}
+		if info, err := keys.Inspect(config.ExpandPath(host.IdentityFile)); err == nil && info.PublicKey != nil {
+			result[host.Host] = info.Fingerprint
+		}
+	}
+	return result
+}
+
+// refreshAgent 重新读取 agent 身份，并标记各主机的密钥是否已加载
+func (m *Model) refreshAgent() {
+	var identities []agent.Identity
+	err := withAgent(func(c *agent.Client) error {
+		var err error
+		identities, err = c.List()
+		return err
+	})
+	m.agent.available = err == nil
+	if err != nil {
+		m.err = err
+		m.agentList.SetItems(nil)
+		return
+	}
+
+	// 按指纹将主机关联到身份
+	byFingerprint := map[string][]string{}
+	m.agent.unloaded = nil
+	loaded := agent.LoadedFingerprints(identities)
+	fingerprints := m.hostFingerprints()
+	for _, host := range m.sshConfig.GetHosts() {
+		fingerprint, ok := fingerprints[host.Host]
+		if !ok {
+			continue
+		}
+		if loaded[fingerprint] {
+			byFingerprint[fingerprint] = append(byFingerprint[fingerprint], host.Host)
+		} else {
+			m.agent.unloaded = append(m.agent.unloaded, host.Host)
+		}
+	}
+
+	items := make([]list.Item, len(identities))
+	for i, identity := range identities {
+		fingerprint := identity.Fingerprint
+		if cert, ok := identity.PublicKey.(*ssh.Certificate); ok {
+			fingerprint = ssh.FingerprintSHA256(cert.Key)
+		}
+		items[i] = AgentItem{identity: identity, hosts: byFingerprint[fingerprint]}
+	}
+	m.agentList.SetItems(items)
+}
+
+// openAgent 打开 Agent 视图
+func (m Model) openAgent() (tea.Model, tea.Cmd) {
+	m.err = nil
+	m.agent.mode = agentModeList
+	m.refreshAgent()
+	m.state = AgentView
+	return m, nil
+}
+
+// updateAgentView 更新 Agent 视图
+func (m Model) updateAgentView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.agent.mode {
+	case agentModeList:
+	case agentModeConfirmRemove, agentModeConfirmRemoveAll:
+		switch msg.String() {
+		case "y", "Y":
+			m.confirmAgentRemove()
+		case "n", "N", "esc":
+			m.agent.mode = agentModeList
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+		return m, nil
+	default:
+		return m.updateAgentForm(msg)
+	}
+	m.status = ""
+
+	switch keypress := msg.String(); keypress {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.state = ListView
+		m.err = nil
+		return m, nil
+	case "r":
+		m.err = nil
+		m.refreshAgent()
+		return m, nil
+	case "a":
+		value := ""
+		if host, ok := m.selectedHost(); ok {
+			value = host.IdentityFile
+		}
+		labels := []string{"私钥:", "口令:", "有效期:", "使用前确认:"}
+		inputs := []textinput.Model{
+			newTextInput("例如: ~/.ssh/id_ed25519", value, 50),
+			newPasswordInput("私钥未加密可留空"),
+			newTextInput("例如: 1h、30m，留空表示不限", "", 20),
+			newTextInput("y/n", "n", 5),
+		}
+		m.agent.form = newInputForm(labels, inputs)
+		m.agent.mode = agentModeAdd
+		return m, textinput.Blink
+	case "L":
+		m.agent.form = newInputForm([]string{"锁定口令:"}, []textinput.Model{newPasswordInput("")})
+		m.agent.mode = agentModeLock
+		return m, textinput.Blink
+	case "U":
+		m.agent.form = newInputForm([]string{"解锁口令:"}, []textinput.Model{newPasswordInput("")})
+		m.agent.mode = agentModeUnlock
+		return m, textinput.Blink
+	case "d", "x":
+		if _, ok := m.agentList.SelectedItem().(AgentItem); ok {
+			m.agent.mode = agentModeConfirmRemove
+		}
+		return m, nil
+	case "D":
+		if len(m.agentList.Items()) > 0 {
+			m.agent.mode = agentModeConfirmRemoveAll
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.agentList, cmd = m.agentList.Update(msg)
+	return m, cmd
+}
+
+// confirmAgentRemove 执行确认后的移除操作
+func (m *Model) confirmAgentRemove() {
+	mode := m.agent.mode
+	m.agent.mode = agentModeList
+
+	if mode == agentModeConfirmRemoveAll {
+		m.err = withAgent(func(c *agent.Client) error { return c.RemoveAll() })
+		if m.err == nil {
+			m.status = "已移除所有身份"
+		}
+		m.refreshAgent()
+		return
+	}
+
+	item, ok := m.agentList.SelectedItem().(AgentItem)
+	if !ok {
+		return
+	}
+	m.err = withAgent(func(c *agent.Client) error { return c.Remove(item.identity.PublicKey) })
+	if m.err == nil {
+		m.status = fmt.Sprintf("已移除 %s", item.Title())
+	}
+	m.refreshAgent()
+}
+
+// updateAgentForm 处理添加密钥、锁定和解锁表单
+func (m Model) updateAgentForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.agent.mode = agentModeList
+		m.err = nil
+		return m, nil
+	}
+
+	submit, cmd := m.agent.form.update(msg)
+	if !submit {
+		return m, cmd
+	}
+
+	f := m.agent.form
+	var err error
+	switch m.agent.mode {
+	case agentModeAdd:
+		var opts agent.AddOptions
+		if lifetime := f.value(2); lifetime != "" {
+			if opts.Lifetime, err = agent.ParseLifetime(lifetime); err != nil {
+				m.err = err
+				return m, nil
+			}
+		}
+		opts.Confirm = strings.EqualFold(f.value(3), "y")
+		path := config.ExpandPath(f.value(0))
+		err = withAgent(func(c *agent.Client) error {
+			return c.AddKeyFile(path, []byte(f.inputs[1].Value()), opts)
+		})
+		if err == nil {
+			m.status = fmt.Sprintf("已添加 %s", config.ContractPath(path))
+		}
+	case agentModeLock:
+		err = withAgent(func(c *agent.Client) error { return c.Lock([]byte(f.inputs[0].Value())) })
+		if err == nil {
+			m.status = "agent 已锁定"
+		}
+	case agentModeUnlock:
+		err = withAgent(func(c *agent.Client) error { return c.Unlock([]byte(f.inputs[0].Value())) })
+		if err == nil {
+			m.status = "agent 已解锁"
+		}
+	}
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.err = nil
+	m.agent.mode = agentModeList
+	m.refreshAgent()
+	return m, nil
+}
+
+// agentView 渲染 Agent 视图
+func (m Model) agentView() string {
+	var content strings.Builder
+
+	if m.agent.mode != agentModeList && m.agent.mode != agentModeConfirmRemove && m.agent.mode != agentModeConfirmRemoveAll {
+		titles := map[agentMode]string{
+			agentModeAdd:    "添加密钥到 ssh-agent",
+			agentModeLock:   "锁定 ssh-agent",
+			agentModeUnlock: "解锁 ssh-agent",
+		}
+		content.WriteString(titleStyle.Render(titles[m.agent.mode]))
+		content.WriteString("\n\n")
+		content.WriteString(GetFormStyle(m.width).Render(m.agent.form.view()))
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("Tab: 下一个字段 • Enter: 确认（在最后一个字段时） • Esc: 取消"))
+		return content.String()
+	}
+
+	content.WriteString(m.agentList.View())
+	content.WriteString("\n")
+	if m.agent.available && len(m.agent.unloaded) > 0 {
+		content.WriteString(warningStyle.Render(fmt.Sprintf("未加载密钥的主机: %s", strings.Join(m.agent.unloaded, ", "))))
+		content.WriteString("\n")
+	}
+	switch m.agent.mode {
+	case agentModeConfirmRemove:
+		if item, ok := m.agentList.SelectedItem().(AgentItem); ok {
+			content.WriteString(warningStyle.Render(fmt.Sprintf("确定从 agent 中移除 %s 吗？[Y] 确认 [N] 取消", item.Title())))
+			content.WriteString("\n")
+		}
+	case agentModeConfirmRemoveAll:
+		content.WriteString(warningStyle.Render(fmt.Sprintf("确定移除 agent 中的全部 %d 个身份吗？[Y] 确认 [N] 取消", len(m.agentList.Items()))))
+		content.WriteString("\n")
+	}
+	content.WriteString(m.renderMessages())
+
+	helpText := []string{
+		"a: 添加密钥",
+		"d/x: 移除",
+		"D: 全部移除",
+		"L/U: 锁定/解锁",
+		"r: 刷新",
+		"Esc: 返回",
+	}
+	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
+
+	return content.String()
+}
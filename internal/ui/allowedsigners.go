@@ -0,0 +1,346 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/allowedsigners"
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/gitconfig"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+)
+
+// signersMode 表示 allowed_signers 视图当前的操作
+type signersMode int
+
+const (
+	signersModeList signersMode = iota
+	signersModeForm
+	signersModeConfirmDelete
+)
+
+// 签名者表单中各字段的位置
+const (
+	signerFieldPrincipals = iota
+	signerFieldKey
+	signerFieldNamespaces
+	signerFieldValidAfter
+	signerFieldValidBefore
+)
+
+// SignerItem 实现 list.Item 接口，表示 allowed_signers 中的一条记录
+type SignerItem struct {
+	entry allowedsigners.Entry
+	local string
+}
+
+func (i SignerItem) FilterValue() string {
+	return strings.Join(i.entry.Principals, ",")
+}
+
+func (i SignerItem) Title() string {
+	title := strings.Join(i.entry.Principals, ", ")
+	if i.entry.CertAuthority {
+		title += " (cert-authority)"
+	}
+	return title
+}
+
+func (i SignerItem) Description() string {
+	parts := []string{keys.KeyTypeName(i.entry.Key.Type()) + " " + i.entry.Fingerprint}
+	if len(i.entry.Namespaces) > 0 {
+		parts = append(parts, "命名空间: "+strings.Join(i.entry.Namespaces, ","))
+	}
+	switch {
+	case i.entry.ValidAfter != "" && i.entry.ValidBefore != "":
+		parts = append(parts, fmt.Sprintf("有效期: %s ~ %s", i.entry.ValidAfter, i.entry.ValidBefore))
+	case i.entry.ValidAfter != "":
+		parts = append(parts, fmt.Sprintf("有效期: %s 起", i.entry.ValidAfter))
+	case i.entry.ValidBefore != "":
+		parts = append(parts, fmt.Sprintf("有效期: 至 %s", i.entry.ValidBefore))
+	}
+	if i.local != "" {
+		parts = append(parts, "本机 "+i.local)
+	} else if i.entry.Comment != "" {
+		parts = append(parts, i.entry.Comment)
+	}
+	return strings.Join(parts, " • ")
+}
+
+// signersState 保存 allowed_signers 视图的状态
+type signersState struct {
+	file          *allowedsigners.File
+	mode          signersMode
+	form          inputForm
+	certAuthority bool
+	editing       *allowedsigners.Entry
+	local         map[string]string
+	returnState   ViewState
+}
+
+// newSignersList 创建签名者列表
+func newSignersList() list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "allowed_signers"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+	return l
+}
+
+// allowedSignersPath 返回全局 git 配置中 gpg.ssh.allowedSignersFile 指定的文件，未设置时为 ~/.ssh/allowed_signers
+func allowedSignersPath() (string, error) {
+	if path, err := gitconfig.GlobalConfigPath(); err == nil {
+		if global, err := gitconfig.Load(path); err == nil {
+			if signers := global.Signing().AllowedSigners; signers != "" {
+				return config.ExpandPath(signers), nil
+			}
+		}
+	}
+	return allowedsigners.DefaultPath()
+}
+
+// openAllowedSigners 打开 allowed_signers 编辑视图，path 为空时使用全局配置中的文件
+func (m Model) openAllowedSigners(path string, returnState ViewState) (tea.Model, tea.Cmd) {
+	if path == "" {
+		var err error
+		if path, err = allowedSignersPath(); err != nil {
+			m.err = err
+			return m, nil
+		}
+	}
+	file, err := allowedsigners.Load(config.ExpandPath(path))
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.signers = signersState{file: file, local: localFingerprints(), returnState: returnState}
+	m.err = nil
+	m.status = ""
+	m.refreshSignersList()
+	m.state = AllowedSignersView
+	return m, nil
+}
+
+// refreshSignersList 刷新签名者列表
+func (m *Model) refreshSignersList() {
+	items := make([]list.Item, len(m.signers.file.Entries))
+	for i, entry := range m.signers.file.Entries {
+		items[i] = SignerItem{entry: entry, local: m.signers.local[entry.Fingerprint]}
+	}
+	m.signersList.SetItems(items)
+	m.signersList.Title = "allowed_signers: " + config.ContractPath(m.signers.file.Path)
+}
+
+// openSignerForm 打开添加或编辑签名者的表单
+func (m *Model) openSignerForm(entry *allowedsigners.Entry) tea.Cmd {
+	s := &m.signers
+	principals, key, namespaces, after, before := "", "", "git", "", ""
+	certAuthority := false
+	if entry != nil {
+		principals = strings.Join(entry.Principals, ",")
+		key = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(entry.Key)))
+		if entry.Comment != "" {
+			key += " " + entry.Comment
+		}
+		namespaces = strings.Join(entry.Namespaces, ",")
+		after, before = entry.ValidAfter, entry.ValidBefore
+		certAuthority = entry.CertAuthority
+	}
+	s.form = newInputForm(
+		[]string{"主体:", "公钥:", "命名空间:", "valid-after:", "valid-before:"},
+		[]textinput.Model{
+			newTextInput("提交邮箱，多个用逗号分隔，可用 * 通配", principals, 40),
+			newTextInput("公钥内容，或 .pub/私钥文件路径", key, 50),
+			newTextInput("留空不限制；git 签名为 git", namespaces, 20),
+			newTextInput("YYYYMMDD[HHMM[SS]][Z]，可留空", after, 20),
+			newTextInput("YYYYMMDD[HHMM[SS]][Z]，可留空", before, 20),
+		},
+	)
+	s.certAuthority = certAuthority
+	s.editing = entry
+	s.mode = signersModeForm
+	return textinput.Blink
+}
+
+// parseSignerKey 解析表单中的公钥：authorized_keys 格式的内容，或公钥/私钥文件路径
+func parseSignerKey(value string) (ssh.PublicKey, string, error) {
+	if value == "" {
+		return nil, "", fmt.Errorf("请填写公钥")
+	}
+	if pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(value)); err == nil {
+		return pub, comment, nil
+	}
+	path := strings.TrimSuffix(config.ExpandPath(value), ".pub")
+	pub, comment, err := keys.PublicKey(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("无法读取公钥 %s: %w", value, err)
+	}
+	return pub, comment, nil
+}
+
+// buildSigner 根据表单内容生成记录，编辑时保留无法识别的选项
+func (m Model) buildSigner() (allowedsigners.Entry, error) {
+	s := m.signers
+	entry := allowedsigners.Entry{
+		Principals:    splitList(s.form.value(signerFieldPrincipals)),
+		CertAuthority: s.certAuthority,
+		Namespaces:    splitList(s.form.value(signerFieldNamespaces)),
+		ValidAfter:    s.form.value(signerFieldValidAfter),
+		ValidBefore:   s.form.value(signerFieldValidBefore),
+	}
+	if s.editing != nil {
+		entry.Other = s.editing.Other
+	}
+	var err error
+	if entry.Key, entry.Comment, err = parseSignerKey(s.form.value(signerFieldKey)); err != nil {
+		return entry, err
+	}
+	if entry.CertAuthority {
+		if _, ok := entry.Key.(*ssh.Certificate); ok {
+			return entry, fmt.Errorf("cert-authority 记录应填写 CA 的公钥，而不是证书")
+		}
+	}
+	entry.Fingerprint = ssh.FingerprintSHA256(entry.Key)
+	return entry, entry.Validate()
+}
+
+// updateAllowedSignersView 更新 allowed_signers 视图
+func (m Model) updateAllowedSignersView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.signers
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch s.mode {
+	case signersModeForm:
+		return m.updateSignerForm(msg)
+	case signersModeConfirmDelete:
+		switch msg.String() {
+		case "y", "Y":
+			s.mode = signersModeList
+			item, ok := m.signersList.SelectedItem().(SignerItem)
+			if !ok {
+				return m, nil
+			}
+			s.file.Remove(item.entry)
+			if err := s.file.Save(); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.err = nil
+			m.status = fmt.Sprintf("已删除 %s", strings.Join(item.entry.Principals, ","))
+			m.refreshSignersList()
+		case "n", "N", "esc":
+			s.mode = signersModeList
+		}
+		return m, nil
+	}
+
+	m.status = ""
+	switch msg.String() {
+	case "esc", "q":
+		m.state = s.returnState
+		m.err = nil
+		return m, nil
+	case "a", "n":
+		m.err = nil
+		return m, m.openSignerForm(nil)
+	case "e", "enter":
+		if item, ok := m.signersList.SelectedItem().(SignerItem); ok {
+			entry := item.entry
+			m.err = nil
+			return m, m.openSignerForm(&entry)
+		}
+		return m, nil
+	case "d", "x":
+		if _, ok := m.signersList.SelectedItem().(SignerItem); ok {
+			s.mode = signersModeConfirmDelete
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.signersList, cmd = m.signersList.Update(msg)
+	return m, cmd
+}
+
+// updateSignerForm 处理签名者表单
+func (m Model) updateSignerForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.signers
+	switch msg.String() {
+	case "esc":
+		s.mode = signersModeList
+		m.err = nil
+		return m, nil
+	case "ctrl+o":
+		s.certAuthority = !s.certAuthority
+		return m, nil
+	}
+
+	submit, cmd := s.form.update(msg)
+	if !submit {
+		return m, cmd
+	}
+	entry, err := m.buildSigner()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if s.editing != nil {
+		s.file.Update(*s.editing, entry)
+	} else {
+		s.file.Add(entry)
+	}
+	if err := s.file.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	s.mode = signersModeList
+	m.err = nil
+	m.status = fmt.Sprintf("已保存 %s", strings.Join(entry.Principals, ","))
+	m.refreshSignersList()
+	return m, nil
+}
+
+// allowedSignersView 渲染 allowed_signers 视图
+func (m Model) allowedSignersView() string {
+	var content strings.Builder
+	s := m.signers
+
+	if s.mode == signersModeForm {
+		title := "添加签名者"
+		if s.editing != nil {
+			title = "编辑签名者: " + strings.Join(s.editing.Principals, ",")
+		}
+		content.WriteString(titleStyle.Render(title))
+		content.WriteString("\n\n")
+		content.WriteString(GetFormStyle(m.width).Render(s.form.view()))
+		content.WriteString("\n")
+		if s.certAuthority {
+			content.WriteString("cert-authority: 是（信任该 CA 签发给上述主体的证书）\n")
+		} else {
+			content.WriteString("cert-authority: 否\n")
+		}
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("Tab: 切换字段 • Ctrl+O: 切换 cert-authority • Enter: 保存 • Esc: 取消"))
+		return content.String()
+	}
+
+	content.WriteString(m.signersList.View())
+	content.WriteString("\n")
+	if s.mode == signersModeConfirmDelete {
+		content.WriteString(warningStyle.Render("确定删除选中的签名者吗？[Y] 确认 [N] 取消"))
+		content.WriteString("\n")
+	}
+	content.WriteString(m.renderMessages())
+	content.WriteString(helpStyle.Render("a/n: 添加 • e/Enter: 编辑 • d/x: 删除 • Esc: 返回"))
+	return content.String()
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+)
+
+// maxSuggestions 下拉框中最多显示的建议数量
+const maxSuggestions = 6
+
+// fieldSuggestions 返回指定表单字段在当前输入下的候选值
+func (m Model) fieldSuggestions(index int, value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	var candidates []string
+	switch index {
+	case 1: // HostName
+		candidates = m.sshConfig.HostNameSuggestions()
+	case 2: // User
+		candidates = m.sshConfig.UserSuggestions()
+	case 4: // IdentityFile
+		return limitSuggestions(config.CompletePath(value, isNoiseFile), value)
+	case 5: // CertificateFile
+		return limitSuggestions(config.CompletePath(value, isNotCertFile), value)
+	default:
+		return nil
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(value)) {
+			matches = append(matches, candidate)
+		}
+	}
+	return limitSuggestions(matches, value)
+}
+
+// limitSuggestions 去掉与当前输入完全相同的候选值并限制数量
+func limitSuggestions(candidates []string, value string) []string {
+	var result []string
+	for _, candidate := range candidates {
+		if candidate == value {
+			continue
+		}
+		result = append(result, candidate)
+		if len(result) == maxSuggestions {
+			break
+		}
+	}
+	return result
+}
+
+// refreshSuggestions 根据当前聚焦字段的内容重新计算下拉建议
+func (m *Model) refreshSuggestions() {
+	m.form.suggestions = nil
+	m.form.suggestionIndex = 0
+	if m.form.focusIndex >= len(m.form.inputs) {
+		return
+	}
+	m.form.suggestions = m.fieldSuggestions(m.form.focusIndex, m.form.inputs[m.form.focusIndex].Value())
+}
+
+// moveSuggestion 在下拉建议中移动选中项
+func (m *Model) moveSuggestion(delta int) {
+	if len(m.form.suggestions) == 0 {
+		return
+	}
+	m.form.suggestionIndex = (m.form.suggestionIndex + delta + len(m.form.suggestions)) % len(m.form.suggestions)
+}
+
+// acceptSuggestion 将选中的建议写入当前字段，返回是否进行了补全
+func (m *Model) acceptSuggestion() bool {
+	if len(m.form.suggestions) == 0 || m.form.focusIndex >= len(m.form.inputs) {
+		return false
+	}
+	input := &m.form.inputs[m.form.focusIndex]
+	input.SetValue(m.form.suggestions[m.form.suggestionIndex])
+	input.CursorEnd()
+	m.refreshSuggestions()
+	return true
+}
+
+// renderSuggestions 渲染当前字段下方的下拉建议
+func (m Model) renderSuggestions() string {
+	lines := make([]string, len(m.form.suggestions))
+	for i, suggestion := range m.form.suggestions {
+		if i == m.form.suggestionIndex {
+			lines[i] = focusedStyle.Render("> " + suggestion)
+		} else {
+			lines[i] = "  " + suggestion
+		}
+	}
+	return dropdownStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/agent"
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/knownhosts"
+	"github.com/allanpk716/git_ssh_tui/internal/probe"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// connTestMsg 连接测试完成后的消息，run 标识发起测试的那一次运行
+type connTestMsg struct {
+	run    int
+	result *probe.Result
+}
+
+// connTestState 保存连接测试视图的状态
+type connTestState struct {
+	host        config.SSHHost
+	running     bool
+	result      *probe.Result
+	cancel      context.CancelFunc
+	returnState ViewState
+	// run 在每次开始测试时递增，用于丢弃已取消的测试迟到的结果
+	run int
+}
+
+// hostTarget 根据主机配置生成连接参数，未设置的字段按 OpenSSH 的默认值处理
+func hostTarget(host config.SSHHost) probe.Target {
+	name, port := hostAddress(host)
+	target := probe.Target{
+		HostName: name,
+		Port:     port,
+		User:     host.User,
+	}
+	if target.User == "" {
+		if current, err := user.Current(); err == nil {
+			target.User = current.Username
+		}
+	}
+	if host.IdentityFile != "" {
+		target.IdentityFile = config.ExpandPath(host.IdentityFile)
+	}
+	if host.CertificateFile != "" {
+		target.CertificateFile = config.ExpandPath(host.CertificateFile)
+	}
+	if path, err := knownhosts.DefaultPath(); err == nil {
+		target.KnownHostsPath = path
+	}
+	return target
+}
+
+// runConnTest 在后台执行连接测试，可用时通过 ssh-agent 认证
+func runConnTest(ctx context.Context, run int, target probe.Target) tea.Cmd {
+	return func() tea.Msg {
+		if client, err := agent.Dial(); err == nil {
+			defer client.Close()
+			target.Agent = client.Agent()
+		}
+		return connTestMsg{run: run, result: probe.Test(ctx, target)}
+	}
+}
+
+// openConnTest 对选中主机开始连接测试
+func (m Model) openConnTest() (tea.Model, tea.Cmd) {
+	host, ok := m.selectedHost()
+	if !ok {
+		return m, nil
+	}
+	return m.startConnTest(host)
+}
+
+// startConnTest 对指定主机开始连接测试，结束后返回当前视图
+func (m Model) startConnTest(host config.SSHHost) (tea.Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.connTest = connTestState{
+		host:        host,
+		running:     true,
+		cancel:      cancel,
+		returnState: m.state,
+		run:         m.connTest.run + 1,
+	}
+	m.err = nil
+	m.state = ConnTestView
+	return m, runConnTest(ctx, m.connTest.run, hostTarget(host))
+}
+
+// handleConnTestResult 处理测试结果；用户已离开视图或结果来自之前取消的测试时丢弃
+func (m Model) handleConnTestResult(msg connTestMsg) (tea.Model, tea.Cmd) {
+	if m.state != ConnTestView || !m.connTest.running || msg.run != m.connTest.run {
+		return m, nil
+	}
+	m.connTest.running = false
+	m.connTest.cancel()
+	m.connTest.result = msg.result
+	return m, nil
+}
+
+// updateConnTestView 更新连接测试视图
+func (m Model) updateConnTestView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		if m.connTest.running {
+			m.connTest.cancel()
+		}
+		return m, tea.Quit
+	case "esc", "q":
+		if m.connTest.running {
+			m.connTest.cancel()
+			m.connTest.running = false
+		}
+		m.state = m.connTest.returnState
+		return m, nil
+	case "r":
+		if !m.connTest.running {
+			ctx, cancel := context.WithCancel(context.Background())
+			m.connTest.running = true
+			m.connTest.result = nil
+			m.connTest.cancel = cancel
+			m.connTest.run++
+			return m, runConnTest(ctx, m.connTest.run, hostTarget(m.connTest.host))
+		}
+	}
+	return m, nil
+}
+
+// connTestView 渲染连接测试结果
+func (m Model) connTestView() string {
+	var content strings.Builder
+	t := m.connTest
+	target := hostTarget(t.host)
+
+	content.WriteString(titleStyle.Render(fmt.Sprintf("连接测试: %s", t.host.Host)))
+	content.WriteString("\n\n")
+	content.WriteString(fmt.Sprintf("%s@%s\n\n", target.User, knownhosts.Address(target.HostName, target.Port)))
+
+	if t.running {
+		content.WriteString("正在测试...\n\n")
+		content.WriteString(helpStyle.Render("Esc: 取消"))
+		return content.String()
+	}
+
+	if r := t.result; r != nil {
+		for _, step := range r.Steps {
+			line := fmt.Sprintf("%-10s %s（%dms）", step.Name, step.Detail, step.Duration.Milliseconds())
+			switch step.Status {
+			case probe.StepOK:
+				content.WriteString(successStyle.Render("✓ " + line))
+			case probe.StepWarning:
+				content.WriteString(warningStyle.Render("! " + line))
+			case probe.StepFailed:
+				content.WriteString(errorStyle.Render("✗ " + line))
+			}
+			content.WriteString("\n")
+		}
+		if r.ServerVersion != "" {
+			content.WriteString(fmt.Sprintf("\n服务器版本: %s\n", r.ServerVersion))
+		}
+		if banner := strings.TrimSpace(r.Banner); banner != "" {
+			content.WriteString(fmt.Sprintf("\n服务器横幅:\n%s\n", banner))
+		}
+		if r.Greeting != "" {
+			content.WriteString(fmt.Sprintf("\n服务器消息:\n%s\n", r.Greeting))
+		}
+		content.WriteString("\n")
+		if r.OK() {
+			content.WriteString(successStyle.Render("连接测试通过"))
+		} else {
+			content.WriteString(errorStyle.Render("连接测试失败"))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString(helpStyle.Render("r: 重新测试 • Esc: 返回"))
+	return content.String()
+}
@@ -0,0 +1,387 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/clipboard"
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/gitconfig"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+)
+
+// deployKeysStep 表示批量部署密钥视图当前所在的步骤
+type deployKeysStep int
+
+const (
+	deployKeysStepInput deployKeysStep = iota
+	deployKeysStepReview
+	deployKeysStepRunning
+	deployKeysStepDone
+)
+
+// deployKeyRow 表示一个仓库的部署密钥计划；err 不为空的行不会执行
+type deployKeyRow struct {
+	url      string
+	remote   gitconfig.Remote
+	host     config.SSHHost
+	keyPath  string
+	cloneURL string
+	err      error
+	pub      string
+}
+
+// deployKeysMsg 后台生成密钥完成后的消息，与计划中的行一一对应
+type deployKeysMsg struct {
+	pubs []ssh.PublicKey
+	errs []error
+}
+
+// deployKeysState 保存批量部署密钥视图的状态
+type deployKeysState struct {
+	step    deployKeysStep
+	input   textarea.Model
+	rows    []deployKeyRow
+	results []stepResult
+}
+
+// aliasUnsafe 匹配不适合出现在别名和文件名中的字符
+var aliasUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// openDeployKeys 打开批量部署密钥视图
+func (m Model) openDeployKeys() (tea.Model, tea.Cmd) {
+	input := textarea.New()
+	input.Placeholder = "git@github.com:org/repo.git"
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+	input.SetWidth(m.width - 4)
+	input.SetHeight(12)
+	if m.deployKeys.input.Value() != "" {
+		input.SetValue(m.deployKeys.input.Value())
+	}
+	m.deployKeys = deployKeysState{input: input}
+	m.err = nil
+	m.status = ""
+	m.state = DeployKeysView
+	return m, m.deployKeys.input.Focus()
+}
+
+// aliasPrefix 返回主机对应的别名前缀：优先使用平台模板中的前缀，否则取主机名的第一段
+func aliasPrefix(templates []config.HostTemplate, hostname string) string {
+	for _, t := range templates {
+		if t.Alias != "" && strings.EqualFold(t.HostName, hostname) {
+			return t.Alias
+		}
+	}
+	prefix, _, _ := strings.Cut(hostname, ".")
+	return prefix
+}
+
+// deployKeyPath 返回别名专用密钥的路径
+func deployKeyPath(sshDir, alias string) string {
+	return filepath.Join(sshDir, "id_"+keys.GenerateEd25519+"_"+alias)
+}
+
+// deployKeyConfigured 判断别名是否为之前为同一主机生成的部署密钥别名
+func (m Model) deployKeyConfigured(sshDir, alias, hostname string) bool {
+	host, ok := m.sshConfig.FindHost(alias)
+	return ok && strings.EqualFold(host.HostName, hostname) && host.IdentityFile == config.ContractPath(deployKeyPath(sshDir, alias))
+}
+
+// planDeployKeys 为每个仓库计算别名、密钥路径和改写后的克隆地址。
+// 别名默认为 <前缀>-<仓库名>，与已有别名或同批的其他仓库重名时加上组织名。
+func (m Model) planDeployKeys() ([]deployKeyRow, error) {
+	templates, err := config.LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+	sshDir, err := config.SSHDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []deployKeyRow
+	for _, line := range strings.Split(m.deployKeys.input.Value(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		row := deployKeyRow{url: line}
+		row.remote, row.err = gitconfig.ParseCloneURL(line)
+		if row.err == nil {
+			if host, ok := m.sshConfig.FindHost(row.remote.Host); ok && host.HostName != "" && host.HostName != host.Host {
+				row.err = fmt.Errorf("已在使用别名 %s", host.Host)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("请输入至少一个仓库地址")
+	}
+
+	// 先统计同批中短别名的使用次数，重复时改用带组织名的别名
+	short := func(row deployKeyRow) string {
+		_, name := row.remote.RepoName()
+		return aliasUnsafe.ReplaceAllString(aliasPrefix(templates, row.remote.Host)+"-"+name, "-")
+	}
+	counts := map[string]int{}
+	for _, row := range rows {
+		if row.err == nil {
+			counts[short(row)]++
+		}
+	}
+
+	used := map[string]bool{}
+	for i := range rows {
+		row := &rows[i]
+		if row.err != nil {
+			continue
+		}
+		alias := short(*row)
+		if _, exists := m.sshConfig.FindHost(alias); exists || counts[alias] > 1 {
+			owner, name := row.remote.RepoName()
+			long := aliasUnsafe.ReplaceAllString(aliasPrefix(templates, row.remote.Host)+"-"+owner+"-"+name, "-")
+			// 短别名已经是该仓库之前生成的别名时不改用长别名
+			if !exists || counts[alias] > 1 || !m.deployKeyConfigured(sshDir, alias, row.remote.Host) {
+				alias = long
+			}
+		}
+		if m.deployKeyConfigured(sshDir, alias, row.remote.Host) {
+			row.err = fmt.Errorf("已配置为别名 %s", alias)
+			continue
+		}
+		if _, exists := m.sshConfig.FindHost(alias); exists || used[alias] {
+			row.err = fmt.Errorf("别名 %s 已存在", alias)
+			continue
+		}
+		keyPath := deployKeyPath(sshDir, alias)
+		if _, err := os.Stat(keyPath); err == nil {
+			row.err = fmt.Errorf("%s 已存在", config.ContractPath(keyPath))
+			continue
+		}
+		used[alias] = true
+
+		user := row.remote.User
+		if user == "" {
+			user = "git"
+		}
+		row.host = config.SSHHost{
+			Host:         alias,
+			HostName:     row.remote.Host,
+			User:         user,
+			Port:         row.remote.Port,
+			IdentityFile: config.ContractPath(keyPath),
+		}
+		row.keyPath = keyPath
+		clone := toAlias(row.remote, row.host)
+		row.cloneURL = clone.String()
+	}
+	return rows, nil
+}
+
+// generateDeployKeys 在后台依次为每个有效的行生成密钥
+func generateDeployKeys(rows []deployKeyRow) tea.Cmd {
+	return func() tea.Msg {
+		msg := deployKeysMsg{pubs: make([]ssh.PublicKey, len(rows)), errs: make([]error, len(rows))}
+		for i, row := range rows {
+			if row.err != nil {
+				continue
+			}
+			msg.pubs[i], msg.errs[i] = keys.Generate(row.keyPath, keys.GenerateEd25519, row.host.Host, nil)
+		}
+		return msg
+	}
+}
+
+// handleDeployKeys 为生成成功的仓库添加别名，最后一次写入 ~/.ssh/config 并按官方指纹写入 known_hosts
+func (m Model) handleDeployKeys(msg deployKeysMsg) (tea.Model, tea.Cmd) {
+	d := &m.deployKeys
+	if m.state != DeployKeysView || d.step != deployKeysStepRunning {
+		return m, nil
+	}
+	d.step = deployKeysStepDone
+	d.results = nil
+
+	added := 0
+	seeded := map[string]bool{}
+	var hosts []config.SSHHost
+	for i := range d.rows {
+		row := &d.rows[i]
+		if row.err != nil {
+			continue
+		}
+		if msg.errs[i] != nil {
+			row.err = msg.errs[i]
+			continue
+		}
+		row.pub, _ = keys.EncodePublicKey(msg.pubs[i], row.host.Host, keys.ExportAuthorizedKeys)
+		m.sshConfig.AddHost(row.host)
+		added++
+		hostname, port := row.host.Target()
+		if address := hostname + ":" + port; !seeded[address] {
+			seeded[address] = true
+			hosts = append(hosts, row.host)
+		}
+	}
+	if added == 0 {
+		d.results = append(d.results, stepResult{detail: "没有生成任何密钥"})
+		return m, nil
+	}
+	if err := m.sshConfig.Save(); err != nil {
+		d.results = append(d.results, stepResult{detail: "写入 ~/.ssh/config 失败: " + err.Error()})
+		return m, nil
+	}
+	d.results = append(d.results, stepResult{ok: true, detail: fmt.Sprintf("已生成 %d 个密钥并添加别名", added)})
+	for _, host := range hosts {
+		d.results = append(d.results, seedPinnedHostKeys(host))
+	}
+	m.refreshList()
+	return m, nil
+}
+
+// deployKeysOutput 返回成功的行中的克隆地址或公钥，每行一个
+func (d deployKeysState) deployKeysOutput(pub bool) string {
+	var lines []string
+	for _, row := range d.rows {
+		if row.err != nil || row.pub == "" {
+			continue
+		}
+		if pub {
+			lines = append(lines, strings.TrimSpace(row.pub))
+		} else {
+			lines = append(lines, row.cloneURL)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// updateDeployKeysView 更新批量部署密钥视图
+func (m Model) updateDeployKeysView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := &m.deployKeys
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch d.step {
+	case deployKeysStepInput:
+		switch msg.String() {
+		case "esc":
+			m.state = ListView
+			m.err = nil
+			return m, nil
+		case "ctrl+s":
+			rows, err := m.planDeployKeys()
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			d.rows = rows
+			d.step = deployKeysStepReview
+			m.err = nil
+			return m, nil
+		}
+		var cmd tea.Cmd
+		d.input, cmd = d.input.Update(msg)
+		return m, cmd
+
+	case deployKeysStepReview:
+		switch msg.String() {
+		case "y", "Y", "enter":
+			for _, row := range d.rows {
+				if row.err == nil {
+					d.step = deployKeysStepRunning
+					return m, generateDeployKeys(d.rows)
+				}
+			}
+			m.err = fmt.Errorf("没有可以生成的仓库")
+		case "n", "N", "esc":
+			d.step = deployKeysStepInput
+			m.err = nil
+			return m, d.input.Focus()
+		}
+		return m, nil
+
+	case deployKeysStepRunning:
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "enter", "q":
+		m.state = ListView
+		m.err = nil
+		m.status = ""
+	case "c", "k":
+		pub := msg.String() == "k"
+		method, err := clipboard.Copy(d.deployKeysOutput(pub))
+		if err != nil {
+			m.err = fmt.Errorf("复制失败: %w", err)
+			return m, nil
+		}
+		m.err = nil
+		if pub {
+			m.status = fmt.Sprintf("已通过%s复制全部公钥", method)
+		} else {
+			m.status = fmt.Sprintf("已通过%s复制全部克隆地址", method)
+		}
+	}
+	return m, nil
+}
+
+// deployKeysView 渲染批量部署密钥视图
+func (m Model) deployKeysView() string {
+	var content strings.Builder
+	d := m.deployKeys
+
+	content.WriteString(titleStyle.Render("批量生成部署密钥"))
+	content.WriteString("\n\n")
+
+	switch d.step {
+	case deployKeysStepInput:
+		content.WriteString(d.input.View())
+		content.WriteString("\n\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("每行一个仓库地址（SSH 或 HTTPS），每个仓库生成一个专用密钥和别名 • Ctrl+S: 预览 • Esc: 返回"))
+
+	case deployKeysStepReview:
+		for _, row := range d.rows {
+			if row.err != nil {
+				content.WriteString(errorStyle.Render(fmt.Sprintf("✗ %s: %s", row.url, row.err)))
+				content.WriteString("\n")
+				continue
+			}
+			content.WriteString(fmt.Sprintf("  %s\n", row.url))
+			hostname, port := row.host.Target()
+			content.WriteString(successStyle.Render(fmt.Sprintf("    Host %s → %s:%s  %s", row.host.Host, hostname, port, row.host.IdentityFile)))
+			content.WriteString("\n")
+			content.WriteString(fmt.Sprintf("    克隆: %s\n", row.cloneURL))
+		}
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("y/Enter: 生成 • n/Esc: 返回修改"))
+
+	case deployKeysStepRunning:
+		content.WriteString("正在生成密钥...\n")
+
+	case deployKeysStepDone:
+		content.WriteString(renderStepResults(d.results))
+		content.WriteString("\n")
+		for _, row := range d.rows {
+			if row.err != nil {
+				content.WriteString(errorStyle.Render(fmt.Sprintf("✗ %s: %s", row.url, row.err)))
+			} else {
+				content.WriteString(fmt.Sprintf("%s  %s", row.cloneURL, helpStyle.Render(row.host.IdentityFile+".pub")))
+			}
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("把每个 .pub 添加为对应仓库的部署密钥（也可以在主界面选中别名按 U 上传） • c: 复制克隆地址 • k: 复制公钥 • Enter/Esc: 完成"))
+	}
+	return content.String()
+}
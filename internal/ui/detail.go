@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateHostDetailView 更新主机详情视图
+func (m Model) updateHostDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
+	switch keypress := msg.String(); keypress {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.state = ListView
+		m.err = nil
+		return m, nil
+	case "e":
+		m.editIndex = m.list.Index()
+		m.state = EditView
+		m.isEditing = true
+		m.form = m.createFormWithData(m.editIndex)
+		m.warning = ""
+		return m, nil
+	case "p":
+		return m.openHostExport()
+	case "P":
+		return m.openHostPassphrase()
+	case "S":
+		return m.openSign()
+	case "H":
+		return m.openKnownHosts()
+	case "T":
+		return m.openConnTest()
+	case "L":
+		return m.openRepoCheck()
+	}
+	return m, nil
+}
+
+// hostDetailView 渲染主机详情视图
+func (m Model) hostDetailView() string {
+	var content strings.Builder
+
+	host, ok := m.selectedHost()
+	if !ok {
+		return errorStyle.Render("错误: 无效的选择")
+	}
+
+	content.WriteString(titleStyle.Render(fmt.Sprintf("主机详情: %s", host.Host)))
+	content.WriteString("\n\n")
+
+	var detail strings.Builder
+	fields := [][2]string{
+		{"Host:", host.Host},
+		{"HostName:", host.HostName},
+		{"User:", host.User},
+		{"Port:", host.Port},
+		{"IdentityFile:", host.IdentityFile},
+		{"CertificateFile:", host.CertificateFile},
+	}
+	for _, field := range fields {
+		detail.WriteString(labelStyle.Render(field[0]))
+		detail.WriteString(" ")
+		detail.WriteString(field[1])
+		detail.WriteString("\n")
+	}
+
+	// 密钥信息
+	if host.IdentityFile != "" {
+		// 无法读取的密钥会在下方的安全检查中提示
+		if info, err := keys.Inspect(config.ExpandPath(host.IdentityFile)); err == nil {
+			detail.WriteString("\n")
+			detail.WriteString(labelStyle.Render("密钥:"))
+			detail.WriteString(fmt.Sprintf(" %s (%s)", info.Summary(), info.Format))
+			if info.Encrypted {
+				detail.WriteString("\n" + labelStyle.Render("口令:") + " 已加密")
+			} else if info.PublicKey != nil {
+				detail.WriteString("\n" + labelStyle.Render("口令:") + " 未加密")
+			}
+		}
+	}
+
+	// 证书信息
+	if cert, err := hostCertificate(host); err == nil && cert != nil {
+		detail.WriteString("\n\n")
+		detail.WriteString(labelStyle.Render("证书:"))
+		detail.WriteString(" " + config.ContractPath(cert.Path) + "\n")
+		detail.WriteString(helpStyle.Render(cert.String()))
+	}
+
+	// 安全检查，使用列表刷新时计算的结果
+	detail.WriteString("\n")
+	item, _ := m.list.SelectedItem().(HostItem)
+	for _, warning := range item.warnings {
+		detail.WriteString("\n")
+		detail.WriteString(warningStyle.Render(fmt.Sprintf("⚠️  %s", warning)))
+	}
+	content.WriteString(GetFormStyle(m.width).Render(strings.TrimRight(detail.String(), "\n")))
+	content.WriteString("\n")
+
+	content.WriteString(m.renderMessages())
+
+	helpText := []string{
+		"e: 编辑",
+		"p: 导出公钥",
+		"P: 设置口令",
+		"S: 签发证书",
+		"H: known_hosts",
+		"T: 连接测试",
+		"L: 检查仓库访问",
+		"Esc: 返回",
+	}
+	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
+
+	return content.String()
+}
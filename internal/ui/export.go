@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/clipboard"
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+)
+
+// exportState 保存公钥导出视图的状态
+type exportState struct {
+	keyPath     string
+	pub         ssh.PublicKey
+	comment     string
+	format      int
+	output      string
+	returnState ViewState
+}
+
+// openExport 读取私钥对应的公钥并打开导出视图
+func (m Model) openExport(keyPath string) (tea.Model, tea.Cmd) {
+	pub, comment, err := keys.PublicKey(keyPath)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.export = exportState{
+		keyPath:     keyPath,
+		pub:         pub,
+		comment:     comment,
+		returnState: m.state,
+	}
+	if err := m.export.encode(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = nil
+	m.state = ExportView
+	return m, nil
+}
+
+// openHostExport 导出选中主机 IdentityFile 对应的公钥
+func (m Model) openHostExport() (tea.Model, tea.Cmd) {
+	host, ok := m.selectedHost()
+	if !ok {
+		return m, nil
+	}
+	if host.IdentityFile == "" {
+		m.err = fmt.Errorf("主机 %s 未配置 IdentityFile", host.Host)
+		return m, nil
+	}
+	return m.openExport(config.ExpandPath(host.IdentityFile))
+}
+
+// encode 按当前选择的格式重新编码公钥；该格式不支持此密钥时清空输出，避免复制或写入上一种格式的内容
+func (e *exportState) encode() error {
+	output, err := keys.EncodePublicKey(e.pub, e.comment, keys.ExportFormats[e.format])
+	if err != nil {
+		e.output = ""
+		return err
+	}
+	e.output = output
+	return nil
+}
+
+// writeNewFile 只在文件不存在时创建并写入，目标可能是同名的私钥或其他已有文件，任何格式都不覆盖
+func writeNewFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// updateExportView 更新公钥导出视图
+func (m Model) updateExportView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keypress := msg.String(); keypress {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.state = m.export.returnState
+		m.err = nil
+		return m, nil
+	case "f", "tab":
+		// 切换导出格式
+		m.export.format = (m.export.format + 1) % len(keys.ExportFormats)
+		m.err = m.export.encode()
+		m.status = ""
+	case "c", "y":
+		if m.export.output == "" {
+			return m, nil
+		}
+		method, err := clipboard.Copy(m.export.output)
+		if err != nil {
+			m.err = fmt.Errorf("复制失败: %w", err)
+			return m, nil
+		}
+		m.err = nil
+		m.status = fmt.Sprintf("已通过%s复制 %s 格式的公钥", method, keys.ExportFormats[m.export.format])
+	case "w":
+		if m.export.output == "" {
+			return m, nil
+		}
+		path := m.export.keyPath + keys.ExportFormats[m.export.format].FileSuffix()
+		if err := writeNewFile(path, []byte(m.export.output)); err != nil {
+			if os.IsExist(err) {
+				m.err = nil
+				m.status = fmt.Sprintf("%s 已存在，未覆盖", config.ContractPath(path))
+				return m, nil
+			}
+			m.err = fmt.Errorf("无法写入文件: %w", err)
+			return m, nil
+		}
+		m.err = nil
+		m.status = fmt.Sprintf("已写入 %s", config.ContractPath(path))
+	}
+	return m, nil
+}
+
+// exportView 渲染公钥导出视图
+func (m Model) exportView() string {
+	var content strings.Builder
+
+	content.WriteString(titleStyle.Render("导出公钥"))
+	content.WriteString("\n\n")
+
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("私钥: %s\n", config.ContractPath(m.export.keyPath)))
+	body.WriteString(fmt.Sprintf("指纹: %s\n", ssh.FingerprintSHA256(m.export.pub)))
+	body.WriteString(fmt.Sprintf("格式: %s\n\n", keys.ExportFormats[m.export.format]))
+	body.WriteString(strings.TrimRight(m.export.output, "\n"))
+	content.WriteString(GetFormStyle(m.width).Render(body.String()))
+	content.WriteString("\n")
+
+	content.WriteString(m.renderMessages())
+
+	helpText := []string{"f: 切换格式", "Esc: 返回"}
+	if m.export.output != "" {
+		helpText = append([]string{"c: 复制到剪贴板", "w: 写入文件"}, helpText...)
+	}
+	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
+
+	return content.String()
+}
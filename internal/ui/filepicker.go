@@ -0,0 +1,313 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// filePicker 保存文件选择器的状态
+type filePicker struct {
+	dir         string
+	entries     []os.DirEntry
+	cursor      int
+	offset      int
+	showAll     bool
+	preview     *keys.KeyInfo
+	previewCert *keys.CertInfo
+	previewErr  error
+	target      int
+	returnState ViewState
+}
+
+// isNoiseFile 判断文件是否属于选择密钥时不需要关心的文件
+func isNoiseFile(name string) bool {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "."):
+		return true
+	case strings.HasSuffix(lower, ".pub"), strings.HasSuffix(lower, ".bak"):
+		return true
+	case strings.HasPrefix(lower, "known_hosts"):
+		return true
+	case lower == "authorized_keys", lower == "config", lower == "environment":
+		return true
+	}
+	return false
+}
+
+// isNotCertFile 判断文件是否不是 OpenSSH 证书文件
+func isNotCertFile(name string) bool {
+	return !strings.HasSuffix(strings.ToLower(name), "-cert.pub")
+}
+
+// hidden 判断条目是否应在当前选择器中隐藏
+func (p *filePicker) hidden(entry os.DirEntry) bool {
+	if p.showAll {
+		return false
+	}
+	if p.target == 5 && !entry.IsDir() {
+		return isNotCertFile(entry.Name())
+	}
+	return isNoiseFile(entry.Name())
+}
+
+// load 读取当前目录，目录排在文件之前
+func (p *filePicker) load() error {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return fmt.Errorf("无法读取目录: %w", err)
+	}
+
+	p.entries = p.entries[:0]
+	for _, entry := range entries {
+		if p.hidden(entry) {
+			continue
+		}
+		p.entries = append(p.entries, entry)
+	}
+	sort.SliceStable(p.entries, func(i, j int) bool {
+		if p.entries[i].IsDir() != p.entries[j].IsDir() {
+			return p.entries[i].IsDir()
+		}
+		return p.entries[i].Name() < p.entries[j].Name()
+	})
+
+	p.cursor = 0
+	p.offset = 0
+	p.updatePreview()
+	return nil
+}
+
+// updatePreview 解析光标所在文件的密钥元数据
+func (p *filePicker) updatePreview() {
+	p.preview = nil
+	p.previewCert = nil
+	p.previewErr = nil
+	if p.cursor >= len(p.entries) || p.entries[p.cursor].IsDir() {
+		return
+	}
+	path := filepath.Join(p.dir, p.entries[p.cursor].Name())
+	if !isNotCertFile(path) {
+		p.previewCert, p.previewErr = keys.ParseCertificate(path)
+		return
+	}
+	p.preview, p.previewErr = keys.Inspect(path)
+}
+
+// moveCursor 移动光标并保证其处于可见范围内
+func (p *filePicker) moveCursor(delta, visible int) {
+	if len(p.entries) == 0 {
+		return
+	}
+	p.cursor += delta
+	if p.cursor < 0 {
+		p.cursor = 0
+	} else if p.cursor >= len(p.entries) {
+		p.cursor = len(p.entries) - 1
+	}
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	} else if p.cursor >= p.offset+visible {
+		p.offset = p.cursor - visible + 1
+	}
+	p.updatePreview()
+}
+
+// pickerVisibleRows 返回文件列表可显示的行数
+func (m Model) pickerVisibleRows() int {
+	rows := m.height - 16
+	if rows < 5 {
+		rows = 5
+	}
+	return rows
+}
+
+// openFilePicker 打开文件选择器，选中的路径将写回 target 指定的输入框
+func (m Model) openFilePicker(target int) (tea.Model, tea.Cmd) {
+	dir, err := config.SSHDir()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.picker = filePicker{dir: dir, target: target, returnState: m.state}
+	if err := m.picker.load(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.state = FilePickerView
+	return m, nil
+}
+
+// updateFilePickerView 更新文件选择器视图
+func (m Model) updateFilePickerView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := m.pickerVisibleRows()
+
+	switch keypress := msg.String(); keypress {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = m.picker.returnState
+		return m, nil
+	case "up", "k":
+		m.picker.moveCursor(-1, visible)
+	case "down", "j":
+		m.picker.moveCursor(1, visible)
+	case "pgup":
+		m.picker.moveCursor(-visible, visible)
+	case "pgdown":
+		m.picker.moveCursor(visible, visible)
+	case ".":
+		// 切换是否显示 .pub、known_hosts 等文件
+		m.picker.showAll = !m.picker.showAll
+		if err := m.picker.load(); err != nil {
+			m.err = err
+		}
+	case "backspace", "left", "h":
+		parent := filepath.Dir(m.picker.dir)
+		if parent != m.picker.dir {
+			m.picker.dir = parent
+			if err := m.picker.load(); err != nil {
+				m.err = err
+			}
+		}
+	case "enter", "right", "l":
+		if m.picker.cursor >= len(m.picker.entries) {
+			return m, nil
+		}
+		entry := m.picker.entries[m.picker.cursor]
+		path := filepath.Join(m.picker.dir, entry.Name())
+		if entry.IsDir() {
+			m.picker.dir = path
+			if err := m.picker.load(); err != nil {
+				m.err = err
+			}
+			return m, nil
+		}
+		if keypress != "enter" {
+			return m, nil
+		}
+
+		// 以 ~/ 相对路径写回输入框
+		m.form.inputs[m.picker.target].SetValue(config.ContractPath(path))
+		m.form.inputs[m.picker.target].CursorEnd()
+		m.state = m.picker.returnState
+		m.warning = ""
+		if valid, warning := config.ValidateIdentityFile(path); !valid && m.picker.target == 4 {
+			m.warning = warning
+		}
+	}
+
+	return m, nil
+}
+
+// filePickerView 渲染文件选择器视图
+func (m Model) filePickerView() string {
+	var content strings.Builder
+
+	// 标题
+	if m.picker.target == 5 {
+		content.WriteString(titleStyle.Render("选择证书文件"))
+	} else {
+		content.WriteString(titleStyle.Render("选择密钥文件"))
+	}
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render(config.ContractPath(m.picker.dir)))
+	content.WriteString("\n\n")
+
+	// 文件列表
+	var files strings.Builder
+	if len(m.picker.entries) == 0 {
+		files.WriteString(helpStyle.Render("（空目录）"))
+	}
+	visible := m.pickerVisibleRows()
+	end := m.picker.offset + visible
+	if end > len(m.picker.entries) {
+		end = len(m.picker.entries)
+	}
+	for i := m.picker.offset; i < end; i++ {
+		entry := m.picker.entries[i]
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		if i == m.picker.cursor {
+			files.WriteString(focusedStyle.Render("> " + name))
+		} else {
+			files.WriteString("  " + name)
+		}
+		if i < end-1 {
+			files.WriteString("\n")
+		}
+	}
+	content.WriteString(GetFormStyle(m.width).Render(files.String()))
+	content.WriteString("\n")
+
+	// 密钥预览
+	content.WriteString(m.renderKeyPreview())
+
+	// 错误信息
+	if m.err != nil {
+		content.WriteString("\n")
+		content.WriteString(errorStyle.Render(fmt.Sprintf("错误: %s", m.err.Error())))
+	}
+
+	// 帮助信息
+	content.WriteString("\n\n")
+	helpText := []string{
+		"↑/↓: 移动",
+		"Enter: 选择/进入目录",
+		"Backspace: 上级目录",
+		".: 显示/隐藏 .pub 等文件",
+		"Esc: 取消",
+	}
+	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
+
+	return content.String()
+}
+
+// renderKeyPreview 渲染光标所在文件的密钥信息
+func (m Model) renderKeyPreview() string {
+	if m.picker.previewErr != nil {
+		return warningStyle.Render(fmt.Sprintf("⚠️  %s", m.picker.previewErr.Error()))
+	}
+	if cert := m.picker.previewCert; cert != nil {
+		result := helpStyle.Render(cert.String())
+		for _, warning := range cert.Check(time.Now()) {
+			result += "\n" + warningStyle.Render(fmt.Sprintf("⚠️  %s", warning))
+		}
+		return result
+	}
+	info := m.picker.preview
+	if info == nil {
+		return ""
+	}
+
+	var preview strings.Builder
+	preview.WriteString(fmt.Sprintf("格式: %s", info.Format))
+	if info.PublicKey != nil {
+		preview.WriteString(fmt.Sprintf("\n类型: %s (%d 位)", keys.KeyTypeName(info.Type), info.Bits))
+		preview.WriteString(fmt.Sprintf("\n指纹: %s", info.Fingerprint))
+	}
+	if info.Comment != "" {
+		preview.WriteString(fmt.Sprintf("\n注释: %s", info.Comment))
+	}
+	if info.Encrypted {
+		preview.WriteString("\n口令: 已加密")
+	} else if info.Format != keys.FormatPublic && info.PublicKey != nil {
+		preview.WriteString("\n口令: 未加密")
+	}
+	result := helpStyle.Render(preview.String())
+	if valid, warning := config.ValidateIdentityFile(info.Path); !valid {
+		result += "\n" + warningStyle.Render(fmt.Sprintf("⚠️  %s", warning))
+	}
+	return result
+}
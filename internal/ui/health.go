@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/allanpk716/git_ssh_tui/internal/agent"
+	"github.com/allanpk716/git_ssh_tui/internal/probe"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// healthResultMsg 单个主机检查完成的消息，results 用于区分被取消的旧检查
+type healthResultMsg struct {
+	result  probe.HealthResult
+	results <-chan probe.HealthResult
+}
+
+// healthDoneMsg 全部主机检查完成（或已取消）的消息
+type healthDoneMsg struct {
+	results <-chan probe.HealthResult
+}
+
+// healthState 保存后台健康检查的状态；结果缓存在 Model.health 中，直到下一次检查
+type healthState struct {
+	running bool
+	cancel  context.CancelFunc
+	results <-chan probe.HealthResult
+	pending int
+}
+
+// waitForHealth 等待下一个检查结果
+func waitForHealth(results <-chan probe.HealthResult) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-results
+		if !ok {
+			return healthDoneMsg{results: results}
+		}
+		return healthResultMsg{result: result, results: results}
+	}
+}
+
+// startHealthCheck 在后台并发检查所有具体主机，通配符条目不参与检查
+func (m Model) startHealthCheck() (tea.Model, tea.Cmd) {
+	if m.healthCheck.running {
+		m.healthCheck.cancel()
+	}
+
+	targets := map[string]probe.Target{}
+	for _, host := range m.sshConfig.GetHosts() {
+		if !host.IsPattern() {
+			targets[host.Host] = hostTarget(host)
+		}
+	}
+	if len(targets) == 0 {
+		m.status = "没有可检查的主机"
+		return m, nil
+	}
+
+	// 所有 worker 共用一个 agent 连接，检查结束后关闭
+	ctx, cancel := context.WithCancel(context.Background())
+	if client, err := agent.Dial(); err == nil {
+		for name, target := range targets {
+			target.Agent = client.Agent()
+			targets[name] = target
+		}
+		context.AfterFunc(ctx, func() { client.Close() })
+	}
+
+	results := probe.CheckAll(ctx, targets, probe.HealthWorkers)
+	m.healthCheck = healthState{running: true, cancel: cancel, results: results, pending: len(targets)}
+	m.status = fmt.Sprintf("正在检查 %d 个主机...", len(targets))
+	return m, waitForHealth(results)
+}
+
+// stopHealthCheck 取消正在进行的检查，已得到的结果保留在缓存中
+func (m *Model) stopHealthCheck() {
+	if m.healthCheck.running {
+		m.healthCheck.cancel()
+		m.healthCheck.running = false
+		m.status = "已取消连通性检查"
+	}
+}
+
+// handleHealthResult 缓存检查结果并只更新对应的列表项
+func (m Model) handleHealthResult(msg healthResultMsg) (tea.Model, tea.Cmd) {
+	if !m.healthCheck.running || msg.results != m.healthCheck.results {
+		return m, nil
+	}
+	m.health[msg.result.Name] = msg.result.Health
+	for i, item := range m.list.Items() {
+		if hostItem, ok := item.(HostItem); ok && hostItem.host.Host == msg.result.Name {
+			hostItem.health = msg.result.Health
+			hostItem.checked = true
+			m.list.SetItem(i, hostItem)
+		}
+	}
+	m.healthCheck.pending--
+	if m.state == ListView {
+		m.status = fmt.Sprintf("正在检查，剩余 %d 个主机...", m.healthCheck.pending)
+	}
+	return m, waitForHealth(m.healthCheck.results)
+}
+
+// handleHealthDone 检查全部完成
+func (m Model) handleHealthDone(msg healthDoneMsg) (tea.Model, tea.Cmd) {
+	if !m.healthCheck.running || msg.results != m.healthCheck.results {
+		return m, nil
+	}
+	m.healthCheck.running = false
+	m.healthCheck.cancel()
+	if m.state == ListView {
+		m.status = "连通性检查完成"
+	}
+	return m, nil
+}
+
+// healthIndicator 返回列表中显示的状态标记和延迟
+func healthIndicator(h probe.Health) string {
+	switch h.Status {
+	case probe.StepOK:
+		return fmt.Sprintf("✓ %dms", h.Latency.Milliseconds())
+	case probe.StepWarning:
+		return fmt.Sprintf("! %dms", h.Latency.Milliseconds())
+	}
+	return "✗"
+}
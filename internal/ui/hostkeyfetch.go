@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/allanpk716/git_ssh_tui/internal/knownhosts"
+	"github.com/allanpk716/git_ssh_tui/internal/probe"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/crypto/ssh"
+)
+
+// hostKeysFetchedMsg 获取主机密钥完成后的消息，run 标识发起获取的那一次运行
+type hostKeysFetchedMsg struct {
+	run  int
+	keys []ssh.PublicKey
+	err  error
+}
+
+// fetchHostKeys 在后台连接服务器获取主机密钥
+func fetchHostKeys(ctx context.Context, run int, host, port string) tea.Cmd {
+	return func() tea.Msg {
+		keys, err := probe.FetchHostKeys(ctx, net.JoinHostPort(host, port), probe.DefaultTimeout)
+		return hostKeysFetchedMsg{run: run, keys: keys, err: err}
+	}
+}
+
+// startHostKeyFetch 开始获取当前主机的主机密钥
+func (m Model) startHostKeyFetch() (tea.Model, tea.Cmd) {
+	k := &m.knownHosts
+	ctx, cancel := context.WithCancel(context.Background())
+	k.mode = knownHostsModeFetch
+	k.fetched = nil
+	k.fetching = true
+	k.cancel = cancel
+	k.fetchRun++
+	m.err = nil
+	host, port := hostAddress(k.host)
+	return m, fetchHostKeys(ctx, k.fetchRun, host, port)
+}
+
+// handleHostKeysFetched 处理获取结果；用户已取消或结果来自之前取消的获取时丢弃
+func (m Model) handleHostKeysFetched(msg hostKeysFetchedMsg) (tea.Model, tea.Cmd) {
+	k := &m.knownHosts
+	if k.mode != knownHostsModeFetch || !k.fetching || msg.run != k.fetchRun {
+		return m, nil
+	}
+	k.fetching = false
+	k.cancel()
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	k.fetched = msg.keys
+	return m, nil
+}
+
+// updateHostKeyFetch 处理获取结果界面的按键
+func (m Model) updateHostKeyFetch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := &m.knownHosts
+	switch msg.String() {
+	case "ctrl+c":
+		if k.fetching {
+			k.cancel()
+		}
+		return m, tea.Quit
+	case "esc", "n", "N":
+		if k.fetching {
+			k.cancel()
+			k.fetching = false
+		}
+		k.mode = knownHostsModeList
+		m.err = nil
+		return m, nil
+	case "y", "Y":
+		if k.fetching || len(k.fetched) == 0 {
+			return m, nil
+		}
+		host, port := hostAddress(k.host)
+		k.file.Replace(host, port, k.fetched)
+		k.mode = knownHostsModeList
+		m.saveKnownHosts(fmt.Sprintf("已写入 %s 的 %d 个主机密钥", knownhosts.Address(host, port), len(k.fetched)))
+	}
+	return m, nil
+}
+
+// fetchedKeyStatus 将获取到的密钥与 known_hosts 现有记录和官方指纹目录比较
+func (m Model) fetchedKeyStatus(key ssh.PublicKey) []string {
+	k := m.knownHosts
+	fingerprint := ssh.FingerprintSHA256(key)
+
+	var status []string
+	known, conflict := false, false
+	for _, entry := range k.file.Find(hostAddress(k.host)) {
+		if entry.Marker != "" {
+			continue
+		}
+		if entry.Fingerprint == fingerprint {
+			known = true
+		} else if entry.Key.Type() == key.Type() {
+			conflict = true
+		}
+	}
+	switch {
+	case known:
+		status = append(status, "已在 known_hosts 中")
+	case conflict:
+		status = append(status, "⚠️ 与 known_hosts 中同类型的记录不同")
+	default:
+		status = append(status, "known_hosts 中没有此密钥")
+	}
+
+	if k.hasProvider {
+		if k.provider.Pinned(fingerprint) {
+			status = append(status, "与 "+k.provider.Name+" 公布的指纹一致")
+		} else {
+			status = append(status, "⚠️ 与 "+k.provider.Name+" 公布的指纹不一致")
+		}
+	}
+	return status
+}
+
+// hostKeyFetchView 渲染获取到的主机密钥
+func (m Model) hostKeyFetchView() string {
+	var content strings.Builder
+	k := m.knownHosts
+	address := knownhosts.Address(hostAddress(k.host))
+
+	content.WriteString(titleStyle.Render(fmt.Sprintf("%s 的主机密钥", address)))
+	content.WriteString("\n\n")
+
+	if k.fetching {
+		content.WriteString(fmt.Sprintf("正在连接 %s ...\n\n", address))
+		content.WriteString(helpStyle.Render("Esc: 取消"))
+		return content.String()
+	}
+
+	for _, key := range k.fetched {
+		lines := []string{
+			fmt.Sprintf("%s %d 位", keys.KeyTypeName(key.Type()), keys.KeyBits(key)),
+			ssh.FingerprintSHA256(key),
+			"MD5:" + ssh.FingerprintLegacyMD5(key),
+			"",
+		}
+		lines = append(lines, m.fetchedKeyStatus(key)...)
+		content.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, keys.Randomart(key), "  ", strings.Join(lines, "\n")))
+		content.WriteString("\n\n")
+	}
+	content.WriteString(m.renderMessages())
+
+	if len(k.fetched) > 0 {
+		content.WriteString(warningStyle.Render("请通过可信渠道与服务器管理员核对以上指纹。写入将替换该主机的现有记录，确定吗？[Y] 确认 [N] 取消"))
+	} else {
+		content.WriteString(helpStyle.Render("Esc: 返回"))
+	}
+	return content.String()
+}
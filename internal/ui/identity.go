@@ -0,0 +1,390 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/gitconfig"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// identityMode 表示目录身份视图当前的操作
+type identityMode int
+
+const (
+	identityModeList identityMode = iota
+	identityModeForm
+	identityModeConfirmDelete
+)
+
+// IdentityItem 实现 list.Item 接口，表示一条 includeIf 身份规则
+type IdentityItem struct {
+	rule    gitconfig.IdentityRule
+	alias   string
+	missing bool
+}
+
+func (i IdentityItem) FilterValue() string {
+	return i.rule.GitDir()
+}
+
+func (i IdentityItem) Title() string {
+	title := i.rule.Condition
+	if i.alias != "" {
+		title += " → " + i.alias
+	}
+	return title
+}
+
+func (i IdentityItem) Description() string {
+	var parts []string
+	if i.rule.Name != "" || i.rule.Email != "" {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("%s <%s>", i.rule.Name, i.rule.Email)))
+	}
+	switch {
+	case len(i.rule.Rewrites) > 0:
+		parts = append(parts, fmt.Sprintf("改写 %s", i.rule.Rewrites[0].InsteadOf))
+	case i.rule.SSHCommand != "":
+		parts = append(parts, i.rule.SSHCommand)
+	}
+	parts = append(parts, i.rule.Include)
+	if i.missing {
+		parts = append(parts, "⚠️ 目录不存在")
+	}
+	return strings.Join(parts, " • ")
+}
+
+// identityState 保存目录身份视图的状态
+type identityState struct {
+	file     *gitconfig.File
+	mode     identityMode
+	form     inputForm
+	useAlias bool
+	editing  *gitconfig.IdentityRule
+}
+
+// newIdentityList 创建身份规则列表
+func newIdentityList() list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "目录身份 (includeIf)"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+	return l
+}
+
+// openIdentity 打开目录身份视图
+func (m Model) openIdentity() (tea.Model, tea.Cmd) {
+	path, err := gitconfig.GlobalConfigPath()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	file, err := gitconfig.Load(path)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.identity = identityState{file: file}
+	m.err = nil
+	m.refreshIdentityList()
+	m.state = IdentityView
+	return m, nil
+}
+
+// refreshIdentityList 重新读取规则并刷新列表
+func (m *Model) refreshIdentityList() {
+	var items []list.Item
+	for _, rule := range m.identity.file.IdentityRules() {
+		item := IdentityItem{rule: rule}
+		if host, ok := m.ruleHost(rule); ok {
+			item.alias = host.Host
+		}
+		// 不以 ~ 或 / 开头的模式由 git 在任意层级匹配，无法检查
+		if dir := rule.GitDir(); strings.HasPrefix(dir, "~") || filepath.IsAbs(dir) {
+			if _, err := os.Stat(config.ExpandPath(strings.TrimSuffix(dir, "**"))); err != nil {
+				item.missing = true
+			}
+		}
+		items = append(items, item)
+	}
+	m.identityList.SetItems(items)
+	m.identityList.Title = "目录身份: " + config.ContractPath(m.identity.file.Path)
+}
+
+// ruleHost 找出规则关联的主机配置：URL 改写指向的别名，或 sshCommand 中 -i 指定的密钥所属的别名
+func (m Model) ruleHost(rule gitconfig.IdentityRule) (config.SSHHost, bool) {
+	for _, rewrite := range rule.Rewrites {
+		if remote, err := gitconfig.ParseRemote(rewrite.Base + "repo"); err == nil {
+			if host, ok := m.sshConfig.FindHost(remote.Host); ok {
+				return host, true
+			}
+		}
+	}
+
+	fields, _ := config.SplitCommand(rule.SSHCommand)
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] != "-i" {
+			continue
+		}
+		identity := filepath.Clean(config.ExpandPath(fields[i+1]))
+		for _, host := range m.sshConfig.GetHosts() {
+			if !host.IsPattern() && host.IdentityFile != "" && filepath.Clean(config.ExpandPath(host.IdentityFile)) == identity {
+				return host, true
+			}
+		}
+	}
+	return config.SSHHost{}, false
+}
+
+// openIdentityForm 打开添加或编辑规则的表单；添加时默认关联列表中选中的主机
+func (m *Model) openIdentityForm(rule *gitconfig.IdentityRule) tea.Cmd {
+	s := &m.identity
+	dir, alias, name, email := "~/work/", "", "", ""
+	useAlias := false
+	if rule != nil {
+		dir, name, email = rule.GitDir(), rule.Name, rule.Email
+		if host, ok := m.ruleHost(*rule); ok {
+			alias = host.Host
+		}
+		useAlias = len(rule.Rewrites) > 0
+	} else if host, ok := m.selectedHost(); ok && !host.IsPattern() {
+		alias = host.Host
+	}
+
+	s.form = newInputForm(
+		[]string{"目录:", "SSH 别名:", "user.name:", "user.email:"},
+		[]textinput.Model{
+			newTextInput("例如: ~/work/", dir, 40),
+			newTextInput("~/.ssh/config 中的 Host，可留空", alias, 30),
+			newTextInput("提交时使用的姓名", name, 30),
+			newTextInput("提交时使用的邮箱", email, 40),
+		},
+	)
+	s.useAlias = useAlias
+	s.editing = rule
+	s.mode = identityModeForm
+	return textinput.Blink
+}
+
+// buildIdentityRule 根据表单内容生成规则：sshCommand 方式指定别名的密钥，别名方式把主机名改写为别名
+func (m Model) buildIdentityRule() (gitconfig.IdentityRule, error) {
+	s := m.identity
+	dir, alias := s.form.value(0), s.form.value(1)
+	rule := gitconfig.IdentityRule{
+		Condition: gitconfig.GitDirCondition(dir),
+		Name:      s.form.value(2),
+		Email:     s.form.value(3),
+	}
+	if dir == "" {
+		return rule, fmt.Errorf("目录不能为空")
+	}
+	if rule.Email != "" && !strings.Contains(rule.Email, "@") {
+		return rule, fmt.Errorf("无效的邮箱: %s", rule.Email)
+	}
+	if s.editing != nil && strings.HasPrefix(s.editing.Condition, "gitdir/i:") {
+		rule.Condition = "gitdir/i:" + strings.TrimPrefix(rule.Condition, "gitdir:")
+	}
+
+	// 编辑时沿用原来的引入文件
+	switch {
+	case s.editing != nil:
+		rule.Include = s.editing.Include
+	case alias != "":
+		rule.Include = "~/.gitconfig-" + alias
+	default:
+		rule.Include = "~/.gitconfig-" + filepath.Base(strings.TrimSuffix(filepath.ToSlash(dir), "/"))
+	}
+
+	if alias == "" {
+		if rule.Name == "" && rule.Email == "" {
+			return rule, fmt.Errorf("请至少填写 SSH 别名或提交身份")
+		}
+		return rule, nil
+	}
+	host, ok := m.sshConfig.FindHost(alias)
+	if !ok {
+		return rule, fmt.Errorf("找不到别名 %s", alias)
+	}
+
+	if s.useAlias {
+		rule.Rewrites = aliasRewrites(host, "", false)
+		return rule, nil
+	}
+	if host.IdentityFile == "" {
+		return rule, fmt.Errorf("别名 %s 未配置 IdentityFile，请改用别名方式", alias)
+	}
+	command := "ssh -i " + quoteCommandArg(host.IdentityFile) + " -o IdentitiesOnly=yes"
+	if host.CertificateFile != "" {
+		command += " -o CertificateFile=" + quoteCommandArg(host.CertificateFile)
+	}
+	rule.SSHCommand = command
+	return rule, nil
+}
+
+// aliasRewrites 生成把实际主机地址（可限定路径前缀）改写为别名的规则；
+// 默认端口时同时覆盖 scp 形式和 ssh:// 形式，其他端口只能出现在 ssh:// 形式中
+func aliasRewrites(host config.SSHHost, path string, push bool) []gitconfig.URLRewrite {
+	hostname, port := host.Target()
+	user := host.User
+	if user == "" {
+		user = "git"
+	}
+	var rewrites []gitconfig.URLRewrite
+	address := hostname
+	if port == config.DefaultPort {
+		rewrites = append(rewrites, gitconfig.URLRewrite{
+			Base:      user + "@" + host.Host + ":" + path,
+			InsteadOf: user + "@" + hostname + ":" + path,
+			Push:      push,
+		})
+	} else {
+		address = hostname + ":" + port
+	}
+	rewrites = append(rewrites, gitconfig.URLRewrite{
+		Base:      "ssh://" + user + "@" + host.Host + "/" + path,
+		InsteadOf: "ssh://" + user + "@" + address + "/" + path,
+		Push:      push,
+	})
+	return rewrites
+}
+
+// quoteCommandArg 用反斜杠转义路径中的空白；git 通过 shell 执行 sshCommand，用引号包裹会使 ~ 无法展开
+func quoteCommandArg(arg string) string {
+	return strings.NewReplacer(" ", `\ `, "\t", "\\\t").Replace(arg)
+}
+
+// updateIdentityView 更新目录身份视图
+func (m Model) updateIdentityView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.identity
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch s.mode {
+	case identityModeForm:
+		return m.updateIdentityForm(msg)
+	case identityModeConfirmDelete:
+		switch msg.String() {
+		case "y", "Y":
+			s.mode = identityModeList
+			item, ok := m.identityList.SelectedItem().(IdentityItem)
+			if !ok {
+				return m, nil
+			}
+			if err := s.file.RemoveIdentityRule(item.rule); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.err = nil
+			m.status = fmt.Sprintf("已移除 %s 的规则，%s 保留在磁盘上", item.rule.Condition, item.rule.Include)
+			m.refreshIdentityList()
+		case "n", "N", "esc":
+			s.mode = identityModeList
+		}
+		return m, nil
+	}
+
+	m.status = ""
+	switch msg.String() {
+	case "esc", "q":
+		m.state = ListView
+		m.err = nil
+		return m, nil
+	case "a", "n":
+		m.err = nil
+		return m, m.openIdentityForm(nil)
+	case "e", "enter":
+		if item, ok := m.identityList.SelectedItem().(IdentityItem); ok {
+			rule := item.rule
+			m.err = nil
+			return m, m.openIdentityForm(&rule)
+		}
+		return m, nil
+	case "d", "x":
+		if _, ok := m.identityList.SelectedItem().(IdentityItem); ok {
+			s.mode = identityModeConfirmDelete
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.identityList, cmd = m.identityList.Update(msg)
+	return m, cmd
+}
+
+// updateIdentityForm 处理规则表单
+func (m Model) updateIdentityForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.identity
+	switch msg.String() {
+	case "esc":
+		s.mode = identityModeList
+		m.err = nil
+		return m, nil
+	case "ctrl+t":
+		s.useAlias = !s.useAlias
+		return m, nil
+	}
+
+	submit, cmd := s.form.update(msg)
+	if !submit {
+		return m, cmd
+	}
+	rule, err := m.buildIdentityRule()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := s.file.SaveIdentityRule(s.editing, rule); err != nil {
+		m.err = err
+		return m, nil
+	}
+	s.mode = identityModeList
+	m.err = nil
+	m.status = fmt.Sprintf("已保存 %s → %s", rule.Condition, rule.Include)
+	m.refreshIdentityList()
+	return m, nil
+}
+
+// identityView 渲染目录身份视图
+func (m Model) identityView() string {
+	var content strings.Builder
+	s := m.identity
+
+	if s.mode == identityModeForm {
+		title := "添加目录身份"
+		if s.editing != nil {
+			title = "编辑目录身份: " + s.editing.Condition
+		}
+		content.WriteString(titleStyle.Render(title))
+		content.WriteString("\n\n")
+		content.WriteString(GetFormStyle(m.width).Render(s.form.view()))
+		content.WriteString("\n")
+		if s.useAlias {
+			content.WriteString("SSH 方式: 别名（url.insteadOf 把该目录下仓库的主机名改写为别名）\n")
+		} else {
+			content.WriteString("SSH 方式: core.sshCommand（ssh -i 别名的 IdentityFile -o IdentitiesOnly=yes）\n")
+		}
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("Tab: 切换字段 • Ctrl+T: 切换 SSH 方式 • Enter: 保存 • Esc: 取消"))
+		return content.String()
+	}
+
+	content.WriteString(m.identityList.View())
+	content.WriteString("\n")
+	if s.mode == identityModeConfirmDelete {
+		content.WriteString(warningStyle.Render("确定从全局配置中移除选中的规则吗？[Y] 确认 [N] 取消"))
+		content.WriteString("\n")
+	}
+	content.WriteString(m.renderMessages())
+	content.WriteString(helpStyle.Render("a/n: 添加 • e/Enter: 编辑 • d/x: 移除 • Esc: 返回"))
+	return content.String()
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inputForm 是一组带标签的输入框，用于主机表单之外的各类小表单
+type inputForm struct {
+	labels     []string
+	inputs     []textinput.Model
+	focusIndex int
+}
+
+// newTextInput 创建普通输入框
+func newTextInput(placeholder, value string, width int) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.CharLimit = 256
+	input.Width = width
+	input.SetValue(value)
+	return input
+}
+
+// newInputForm 创建表单并聚焦第一个输入框
+func newInputForm(labels []string, inputs []textinput.Model) inputForm {
+	f := inputForm{labels: labels, inputs: inputs}
+	f.setFocus(0)
+	return f
+}
+
+// setFocus 聚焦指定的输入框
+func (f *inputForm) setFocus(index int) tea.Cmd {
+	f.focusIndex = index
+	cmds := make([]tea.Cmd, len(f.inputs))
+	for i := range f.inputs {
+		if i == f.focusIndex {
+			cmds[i] = f.inputs[i].Focus()
+			f.inputs[i].PromptStyle = focusedStyle
+			f.inputs[i].TextStyle = focusedStyle
+			continue
+		}
+		f.inputs[i].Blur()
+		f.inputs[i].PromptStyle = noStyle
+		f.inputs[i].TextStyle = noStyle
+	}
+	return tea.Batch(cmds...)
+}
+
+// update 处理按键，在最后一个输入框按 Enter 时返回 submit 为 true
+func (f *inputForm) update(msg tea.KeyMsg) (submit bool, cmd tea.Cmd) {
+	switch keypress := msg.String(); keypress {
+	case "tab", "shift+tab", "enter", "up", "down":
+		if keypress == "enter" && f.focusIndex == len(f.inputs)-1 {
+			return true, nil
+		}
+
+		index := f.focusIndex
+		if keypress == "up" || keypress == "shift+tab" {
+			index--
+		} else {
+			index++
+		}
+		if index >= len(f.inputs) {
+			index = 0
+		} else if index < 0 {
+			index = len(f.inputs) - 1
+		}
+		return false, f.setFocus(index)
+	}
+
+	f.inputs[f.focusIndex], cmd = f.inputs[f.focusIndex].Update(msg)
+	return false, cmd
+}
+
+// value 返回指定输入框去除首尾空白后的内容
+func (f inputForm) value(index int) string {
+	return strings.TrimSpace(f.inputs[index].Value())
+}
+
+// view 渲染表单
+func (f inputForm) view() string {
+	var form strings.Builder
+	for i, input := range f.inputs {
+		if i == f.focusIndex {
+			form.WriteString(focusedStyle.Render(f.labels[i]))
+		} else {
+			form.WriteString(labelStyle.Render(f.labels[i]))
+		}
+		form.WriteString(" ")
+		form.WriteString(inputView(input))
+		if i < len(f.inputs)-1 {
+			form.WriteString("\n")
+		}
+	}
+	return form.String()
+}
+
+// inputView 渲染输入框。textinput 按显示宽度截取占位符的 rune，
+// 含中文的占位符会越界，因此显示占位符时不限制宽度。
+func inputView(input textinput.Model) string {
+	if input.Value() == "" {
+		input.Width = 0
+	}
+	return input.View()
+}
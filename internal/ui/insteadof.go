@@ -0,0 +1,349 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/gitconfig"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// insteadOfMode 表示地址改写规则视图当前的操作
+type insteadOfMode int
+
+const (
+	insteadOfModeList insteadOfMode = iota
+	insteadOfModeForm
+	insteadOfModeConfirmDelete
+)
+
+// InsteadOfItem 实现 list.Item 接口，表示一条 url.<base>.insteadOf 规则
+type InsteadOfItem struct {
+	rewrite  gitconfig.URLRewrite
+	alias    string
+	warnings []string
+}
+
+func (i InsteadOfItem) FilterValue() string {
+	return i.rewrite.InsteadOf
+}
+
+func (i InsteadOfItem) Title() string {
+	title := fmt.Sprintf("%s → %s", i.rewrite.InsteadOf, i.rewrite.Base)
+	if i.rewrite.Push {
+		title += " (仅推送)"
+	}
+	return title
+}
+
+func (i InsteadOfItem) Description() string {
+	var parts []string
+	if i.alias != "" {
+		parts = append(parts, "别名 "+i.alias)
+	}
+	for _, warning := range i.warnings {
+		parts = append(parts, "⚠️ "+warning)
+	}
+	if len(parts) == 0 {
+		return "未关联别名"
+	}
+	return strings.Join(parts, " • ")
+}
+
+// insteadOfState 保存地址改写规则视图的状态
+type insteadOfState struct {
+	file    *gitconfig.File
+	mode    insteadOfMode
+	form    inputForm
+	push    bool
+	editing *gitconfig.URLRewrite
+}
+
+// newInsteadOfList 创建地址改写规则列表
+func newInsteadOfList() list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "地址改写 (insteadOf)"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+	return l
+}
+
+// openInsteadOf 打开地址改写规则视图
+func (m Model) openInsteadOf() (tea.Model, tea.Cmd) {
+	path, err := gitconfig.GlobalConfigPath()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	file, err := gitconfig.Load(path)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.insteadOf = insteadOfState{file: file}
+	m.err = nil
+	m.refreshInsteadOfList()
+	m.state = InsteadOfView
+	return m, nil
+}
+
+// splitRewriteURL 把规则中的地址前缀拆成主机和路径前缀，如 git@gh-work:org/ 的主机为 gh-work、路径为 org/
+func splitRewriteURL(prefix string) (gitconfig.Remote, bool) {
+	remote, err := gitconfig.ParseRemote(prefix + "x")
+	if err != nil {
+		return gitconfig.Remote{}, false
+	}
+	remote.Path = strings.TrimSuffix(remote.Path, "x")
+	return remote, true
+}
+
+// refreshInsteadOfList 刷新规则列表，检查别名是否存在、是否与前缀的主机一致以及前缀是否重复
+func (m *Model) refreshInsteadOfList() {
+	rewrites := m.insteadOf.file.URLRewrites()
+	var items []list.Item
+	for i, rewrite := range rewrites {
+		item := InsteadOfItem{rewrite: rewrite}
+		if base, ok := splitRewriteURL(rewrite.Base); ok {
+			if host, ok := m.sshConfig.FindHost(base.Host); ok {
+				item.alias = host.Host
+				hostname, _ := host.Target()
+				if from, ok := splitRewriteURL(rewrite.InsteadOf); ok && !strings.EqualFold(from.Host, hostname) {
+					item.warnings = append(item.warnings, fmt.Sprintf("别名指向 %s，而前缀的主机是 %s", hostname, from.Host))
+				}
+			} else if !strings.Contains(base.Host, ".") {
+				item.warnings = append(item.warnings, fmt.Sprintf("~/.ssh/config 中没有别名 %s", base.Host))
+			}
+		}
+		others := append(append([]gitconfig.URLRewrite{}, rewrites[:i]...), rewrites[i+1:]...)
+		if err := gitconfig.RewriteConflict(others, rewrite); err != nil {
+			item.warnings = append(item.warnings, err.Error())
+		}
+		items = append(items, item)
+	}
+	m.insteadOfList.SetItems(items)
+	m.insteadOfList.Title = "地址改写: " + config.ContractPath(m.insteadOf.file.Path)
+}
+
+// openInsteadOfForm 打开添加或编辑规则的表单；添加时默认使用列表中选中的主机
+func (m *Model) openInsteadOfForm(rewrite *gitconfig.URLRewrite) tea.Cmd {
+	s := &m.insteadOf
+	alias, path, push := "", "", false
+	if rewrite != nil {
+		if base, ok := splitRewriteURL(rewrite.Base); ok {
+			alias, path = base.Host, base.Path
+		}
+		push = rewrite.Push
+	} else if host, ok := m.selectedHost(); ok && !host.IsPattern() {
+		alias = host.Host
+	}
+
+	s.form = newInputForm(
+		[]string{"SSH 别名:", "路径前缀:"},
+		[]textinput.Model{
+			newTextInput("~/.ssh/config 中的 Host", alias, 30),
+			newTextInput("例如: orgA/，留空表示整个主机", path, 40),
+		},
+	)
+	s.push = push
+	s.editing = rewrite
+	s.mode = insteadOfModeForm
+	return textinput.Blink
+}
+
+// buildInsteadOf 根据表单生成规则；编辑时只生成与原规则形式（scp 或 ssh://）相同的一条
+func (m Model) buildInsteadOf() ([]gitconfig.URLRewrite, error) {
+	s := m.insteadOf
+	alias := s.form.value(0)
+	path := strings.TrimPrefix(s.form.value(1), "/")
+	if path != "" && !strings.HasSuffix(path, "/") {
+		// 前缀按字符串匹配，补上 / 避免 orgA 同时匹配 orgAB
+		path += "/"
+	}
+	if alias == "" {
+		return nil, fmt.Errorf("请填写 SSH 别名")
+	}
+	host, ok := m.sshConfig.FindHost(alias)
+	if !ok {
+		return nil, fmt.Errorf("~/.ssh/config 中没有别名 %s", alias)
+	}
+	if hostname, _ := host.Target(); hostname == host.Host {
+		return nil, fmt.Errorf("别名 %s 没有设置不同的 HostName，改写没有意义", alias)
+	}
+
+	rewrites := aliasRewrites(host, path, s.push)
+	if s.editing != nil {
+		sshForm := strings.HasPrefix(s.editing.Base, "ssh://")
+		for _, rewrite := range rewrites {
+			if strings.HasPrefix(rewrite.Base, "ssh://") == sshForm {
+				return []gitconfig.URLRewrite{rewrite}, nil
+			}
+		}
+		return rewrites[:1], nil
+	}
+	return rewrites, nil
+}
+
+// saveInsteadOf 校验并写入规则
+func (m *Model) saveInsteadOf() error {
+	s := &m.insteadOf
+	rewrites, err := m.buildInsteadOf()
+	if err != nil {
+		return err
+	}
+
+	existing := s.file.URLRewrites()
+	if s.editing != nil {
+		for i, rewrite := range existing {
+			if rewrite == *s.editing {
+				existing = append(existing[:i], existing[i+1:]...)
+				break
+			}
+		}
+	}
+	for _, rewrite := range rewrites {
+		if err := gitconfig.RewriteConflict(existing, rewrite); err != nil {
+			return err
+		}
+	}
+
+	if s.editing != nil {
+		s.file.RemoveURLRewrite(*s.editing)
+	}
+	for _, rewrite := range rewrites {
+		s.file.AddURLRewrite(rewrite)
+	}
+	if err := s.file.Save(); err != nil {
+		return err
+	}
+	m.status = fmt.Sprintf("已写入 %d 条改写规则（原文件已备份为 .bak）", len(rewrites))
+	return nil
+}
+
+// updateInsteadOfView 更新地址改写规则视图
+func (m Model) updateInsteadOfView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.insteadOf
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch s.mode {
+	case insteadOfModeForm:
+		switch msg.String() {
+		case "esc":
+			s.mode = insteadOfModeList
+			m.err = nil
+			return m, nil
+		case "ctrl+t":
+			s.push = !s.push
+			return m, nil
+		}
+		submit, cmd := s.form.update(msg)
+		if !submit {
+			return m, cmd
+		}
+		if err := m.saveInsteadOf(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		s.mode = insteadOfModeList
+		m.err = nil
+		m.refreshInsteadOfList()
+		return m, nil
+	case insteadOfModeConfirmDelete:
+		switch msg.String() {
+		case "y", "Y":
+			s.mode = insteadOfModeList
+			item, ok := m.insteadOfList.SelectedItem().(InsteadOfItem)
+			if !ok {
+				return m, nil
+			}
+			s.file.RemoveURLRewrite(item.rewrite)
+			if err := s.file.Save(); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.err = nil
+			m.status = fmt.Sprintf("已删除 %s 的改写规则", item.rewrite.InsteadOf)
+			m.refreshInsteadOfList()
+		case "n", "N", "esc":
+			s.mode = insteadOfModeList
+		}
+		return m, nil
+	}
+
+	m.status = ""
+	switch msg.String() {
+	case "esc", "q":
+		m.state = ListView
+		m.err = nil
+		return m, nil
+	case "a", "n":
+		m.err = nil
+		return m, m.openInsteadOfForm(nil)
+	case "e", "enter":
+		if item, ok := m.insteadOfList.SelectedItem().(InsteadOfItem); ok {
+			rewrite := item.rewrite
+			m.err = nil
+			return m, m.openInsteadOfForm(&rewrite)
+		}
+		return m, nil
+	case "d", "x":
+		if _, ok := m.insteadOfList.SelectedItem().(InsteadOfItem); ok {
+			s.mode = insteadOfModeConfirmDelete
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.insteadOfList, cmd = m.insteadOfList.Update(msg)
+	return m, cmd
+}
+
+// insteadOfView 渲染地址改写规则视图
+func (m Model) insteadOfView() string {
+	var content strings.Builder
+	s := m.insteadOf
+
+	if s.mode == insteadOfModeForm {
+		title := "添加地址改写"
+		if s.editing != nil {
+			title = "编辑地址改写: " + s.editing.InsteadOf
+		}
+		content.WriteString(titleStyle.Render(title))
+		content.WriteString("\n\n")
+		content.WriteString(GetFormStyle(m.width).Render(s.form.view()))
+		content.WriteString("\n")
+		if s.push {
+			content.WriteString("类型: pushInsteadOf（只改写推送地址，拉取仍使用原地址）\n")
+		} else {
+			content.WriteString("类型: insteadOf（拉取和推送都改写）\n")
+		}
+		// 预览将要写入的规则
+		if rewrites, err := m.buildInsteadOf(); err == nil {
+			for _, rewrite := range rewrites {
+				content.WriteString(successStyle.Render(fmt.Sprintf("  %s → %s", rewrite.InsteadOf, rewrite.Base)))
+				content.WriteString("\n")
+			}
+		}
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("Tab: 切换字段 • Ctrl+T: 切换 insteadOf/pushInsteadOf • Enter: 保存 • Esc: 取消"))
+		return content.String()
+	}
+
+	content.WriteString(m.insteadOfList.View())
+	content.WriteString("\n")
+	if s.mode == insteadOfModeConfirmDelete {
+		content.WriteString(warningStyle.Render("确定删除选中的改写规则吗？[Y] 确认 [N] 取消"))
+		content.WriteString("\n")
+	}
+	content.WriteString(m.renderMessages())
+	content.WriteString(helpStyle.Render("a/n: 添加 • e/Enter: 编辑 • d/x: 删除 • Esc: 返回"))
+	return content.String()
+}
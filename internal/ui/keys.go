@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyItem 实现 list.Item 接口，表示密钥清单中的一个私钥
+type KeyItem struct {
+	info  *keys.KeyInfo
+	hosts []string
+}
+
+func (k KeyItem) FilterValue() string {
+	return k.info.Path
+}
+
+func (k KeyItem) Title() string {
+	return config.ContractPath(k.info.Path)
+}
+
+func (k KeyItem) Description() string {
+	desc := k.info.Summary()
+	if k.info.Encrypted {
+		desc += " • 已加密"
+	}
+	if len(k.hosts) > 0 {
+		desc += " • 用于: " + strings.Join(k.hosts, ", ")
+	}
+	if findings := keys.Audit(k.info); len(findings) > 0 {
+		desc += " • ⚠️ " + strings.Join(findings, "；")
+	}
+	return desc
+}
+
+// newKeyList 创建密钥清单列表
+func newKeyList() list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "密钥清单"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+	return l
+}
+
+// loadKeyItems 扫描 ~/.ssh 并合并各主机引用的密钥，生成密钥清单
+func (m Model) loadKeyItems() ([]list.Item, error) {
+	dir, err := config.SSHDir()
+	if err != nil {
+		return nil, err
+	}
+	infos, err := keys.Scan(dir)
+	if err != nil {
+		return nil, fmt.Errorf("无法扫描密钥目录: %w", err)
+	}
+
+	// 记录每个密钥被哪些主机使用
+	usedBy := map[string][]string{}
+	for _, host := range m.sshConfig.GetHosts() {
+		if host.IdentityFile == "" {
+			continue
+		}
+		path := filepath.Clean(config.ExpandPath(host.IdentityFile))
+		if len(usedBy[path]) == 0 && !containsKey(infos, path) {
+			// ~/.ssh 之外的密钥也加入清单
+			if info, err := keys.Inspect(path); err == nil {
+				infos = append(infos, info)
+			}
+		}
+		usedBy[path] = append(usedBy[path], host.Host)
+	}
+
+	items := make([]list.Item, len(infos))
+	for i, info := range infos {
+		items[i] = KeyItem{info: info, hosts: usedBy[filepath.Clean(info.Path)]}
+	}
+	return items, nil
+}
+
+// containsKey 判断清单中是否已包含指定路径的密钥
+func containsKey(infos []*keys.KeyInfo, path string) bool {
+	for _, info := range infos {
+		if filepath.Clean(info.Path) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshKeyList 重新加载密钥清单
+func (m *Model) refreshKeyList() {
+	items, err := m.loadKeyItems()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.keyList.SetItems(items)
+}
+
+// selectedKey 返回密钥清单中当前选中的密钥
+func (m Model) selectedKey() (*keys.KeyInfo, bool) {
+	item, ok := m.keyList.SelectedItem().(KeyItem)
+	if !ok {
+		return nil, false
+	}
+	return item.info, true
+}
+
+// updateKeysView 更新密钥清单视图
+func (m Model) updateKeysView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
+	switch keypress := msg.String(); keypress {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.state = ListView
+		m.err = nil
+		return m, nil
+	case "p":
+		if info, ok := m.selectedKey(); ok {
+			return m.openExport(info.Path)
+		}
+		return m, nil
+	case "P":
+		if info, ok := m.selectedKey(); ok {
+			return m.openPassphrase(info.Path)
+		}
+		return m, nil
+	case "s":
+		if info, ok := m.selectedKey(); ok {
+			return m.openSigning(info)
+		}
+		return m, nil
+	case "V":
+		return m.openAllowedSigners("", KeysView)
+	case "r":
+		m.refreshKeyList()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.keyList, cmd = m.keyList.Update(msg)
+	return m, cmd
+}
+
+// keysView 渲染密钥清单视图
+func (m Model) keysView() string {
+	var content strings.Builder
+
+	content.WriteString(m.keyList.View())
+	content.WriteString("\n")
+	content.WriteString(m.renderMessages())
+
+	helpText := []string{
+		"p: 导出公钥",
+		"P: 设置口令",
+		"s: 提交签名",
+		"V: allowed_signers",
+		"r: 刷新",
+		"Esc: 返回",
+	}
+	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
+
+	return content.String()
+}
+
+// renderMessages 渲染状态和错误信息，每条信息占一行
+func (m Model) renderMessages() string {
+	var messages strings.Builder
+	if m.status != "" {
+		messages.WriteString(successStyle.Render(m.status))
+		messages.WriteString("\n")
+	}
+	if m.err != nil {
+		messages.WriteString(errorStyle.Render(fmt.Sprintf("错误: %s", m.err.Error())))
+		messages.WriteString("\n")
+	}
+	return messages.String()
+}
@@ -0,0 +1,447 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/allanpk716/git_ssh_tui/internal/knownhosts"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+)
+
+// knownHostsMode 表示 known_hosts 视图当前的操作
+type knownHostsMode int
+
+const (
+	knownHostsModeList knownHostsMode = iota
+	knownHostsModeConfirmEntry
+	knownHostsModeConfirmHost
+	knownHostsModeReplace
+	knownHostsModeConfirmSeed
+	knownHostsModeFetch
+)
+
+// KnownHostItem 实现 list.Item 接口，表示 known_hosts 中的一条记录
+type KnownHostItem struct {
+	entry   knownhosts.Entry
+	matched bool
+	pinned  string
+}
+
+func (k KnownHostItem) FilterValue() string {
+	return k.entry.HostsString()
+}
+
+func (k KnownHostItem) Title() string {
+	title := k.entry.HostsString()
+	if k.entry.Marker != "" {
+		title = k.entry.Marker + " " + title
+	}
+	return title
+}
+
+func (k KnownHostItem) Description() string {
+	desc := fmt.Sprintf("第 %d 行 • %s %s", k.entry.Line, keys.KeyTypeName(k.entry.Key.Type()), k.entry.Fingerprint)
+	if k.matched {
+		desc += " • 匹配当前主机"
+	}
+	if k.pinned != "" {
+		desc += " • " + k.pinned
+	}
+	return desc
+}
+
+// knownHostsState 保存 known_hosts 视图的状态
+type knownHostsState struct {
+	file        *knownhosts.File
+	host        config.SSHHost
+	hasHost     bool
+	provider    knownhosts.Provider
+	hasProvider bool
+	showAll     bool
+	mode        knownHostsMode
+	form        inputForm
+	fetched     []ssh.PublicKey
+	fetching    bool
+	cancel      context.CancelFunc
+	// fetchRun 在每次开始获取主机密钥时递增，用于丢弃已取消的获取迟到的结果
+	fetchRun int
+}
+
+// hostAddress 返回主机实际连接的地址和端口，未设置 HostName 时使用 Host 本身
+func hostAddress(host config.SSHHost) (string, string) {
+	return host.Target()
+}
+
+// newKnownHostsList 创建 known_hosts 记录列表
+func newKnownHostsList() list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "known_hosts"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+	return l
+}
+
+// openKnownHosts 打开 known_hosts 视图，默认只显示与选中主机匹配的记录
+func (m Model) openKnownHosts() (tea.Model, tea.Cmd) {
+	path, err := knownhosts.DefaultPath()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	file, err := knownhosts.Load(path)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.knownHosts = knownHostsState{file: file, fetchRun: m.knownHosts.fetchRun}
+	m.knownHosts.host, m.knownHosts.hasHost = m.selectedHost()
+	m.knownHosts.showAll = !m.knownHosts.hasHost
+	if m.knownHosts.hasHost {
+		catalog, err := pinnedCatalog()
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.knownHosts.provider, m.knownHosts.hasProvider = catalog.Lookup(hostAddress(m.knownHosts.host))
+	}
+	m.err = nil
+	m.refreshKnownHosts()
+	m.state = KnownHostsView
+	return m, nil
+}
+
+// refreshKnownHosts 根据过滤条件刷新记录列表
+func (m *Model) refreshKnownHosts() {
+	k := &m.knownHosts
+	address := ""
+	if k.hasHost {
+		address = knownhosts.Address(hostAddress(k.host))
+	}
+
+	var items []list.Item
+	for _, entry := range k.file.Entries {
+		matched := address != "" && entry.Matches(address)
+		if !k.showAll && !matched {
+			continue
+		}
+		item := KnownHostItem{entry: entry, matched: matched}
+		if matched && k.hasProvider && entry.Marker == "" {
+			if k.provider.Pinned(entry.Fingerprint) {
+				item.pinned = "与 " + k.provider.Name + " 公布的指纹一致"
+			} else {
+				item.pinned = "⚠️ 与 " + k.provider.Name + " 公布的指纹不一致"
+			}
+		}
+		items = append(items, item)
+	}
+	m.knownHostsList.SetItems(items)
+
+	if k.hasHost && !k.showAll {
+		m.knownHostsList.Title = fmt.Sprintf("known_hosts: %s", address)
+	} else {
+		m.knownHostsList.Title = "known_hosts: 全部记录"
+	}
+}
+
+// saveKnownHosts 保存 known_hosts 并刷新列表
+func (m *Model) saveKnownHosts(status string) {
+	if err := m.knownHosts.file.Save(); err != nil {
+		m.err = err
+		return
+	}
+	m.err = nil
+	m.status = status + "（原文件已备份为 known_hosts.old）"
+	m.refreshKnownHosts()
+	m.refreshList()
+}
+
+// seedKnownHosts 按官方指纹目录写入当前主机的主机密钥，替换已有的普通记录
+func (m *Model) seedKnownHosts() {
+	k := &m.knownHosts
+	pubs, err := k.provider.PublicKeys()
+	if err != nil {
+		m.err = err
+		return
+	}
+	if len(pubs) == 0 {
+		m.err = fmt.Errorf("指纹目录中只有 %s 的指纹，没有完整公钥，无法直接写入", k.provider.Name)
+		return
+	}
+	host, port := hostAddress(k.host)
+	k.file.Replace(host, port, pubs)
+	m.saveKnownHosts(fmt.Sprintf("已按 %s 公布的指纹写入 %s 的 %d 个主机密钥", k.provider.Name, knownhosts.Address(host, port), len(pubs)))
+}
+
+// updateKnownHostsView 更新 known_hosts 视图
+func (m Model) updateKnownHostsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := &m.knownHosts
+
+	switch k.mode {
+	case knownHostsModeConfirmEntry, knownHostsModeConfirmHost, knownHostsModeConfirmSeed:
+		switch msg.String() {
+		case "y", "Y":
+			if k.mode == knownHostsModeConfirmSeed {
+				k.mode = knownHostsModeList
+				m.seedKnownHosts()
+			} else {
+				m.confirmKnownHostsDelete()
+			}
+		case "n", "N", "esc":
+			k.mode = knownHostsModeList
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+		return m, nil
+	case knownHostsModeReplace:
+		return m.updateKnownHostsReplace(msg)
+	case knownHostsModeFetch:
+		return m.updateHostKeyFetch(msg)
+	}
+
+	m.status = ""
+	switch keypress := msg.String(); keypress {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.state = ListView
+		m.err = nil
+		return m, nil
+	case "f":
+		if k.hasHost {
+			k.showAll = !k.showAll
+			m.refreshKnownHosts()
+		}
+		return m, nil
+	case "d", "x":
+		if _, ok := m.knownHostsList.SelectedItem().(KnownHostItem); ok {
+			k.mode = knownHostsModeConfirmEntry
+		}
+		return m, nil
+	case "D":
+		if k.hasHost {
+			k.mode = knownHostsModeConfirmHost
+		}
+		return m, nil
+	case "F":
+		if k.hasHost {
+			return m.startHostKeyFetch()
+		}
+		return m, nil
+	case "s":
+		if k.hasProvider {
+			k.mode = knownHostsModeConfirmSeed
+		}
+		return m, nil
+	case "R":
+		if k.hasHost {
+			k.form = newInputForm(
+				[]string{"新主机密钥:"},
+				[]textinput.Model{newTextInput("粘贴 ssh-ed25519 AAAA... 形式的公钥", "", 60)},
+			)
+			k.mode = knownHostsModeReplace
+			return m, textinput.Blink
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.knownHostsList, cmd = m.knownHostsList.Update(msg)
+	return m, cmd
+}
+
+// confirmKnownHostsDelete 执行确认后的删除操作
+func (m *Model) confirmKnownHostsDelete() {
+	k := &m.knownHosts
+	mode := k.mode
+	k.mode = knownHostsModeList
+
+	if mode == knownHostsModeConfirmHost {
+		address := knownhosts.Address(hostAddress(k.host))
+		removed := k.file.RemoveHost(hostAddress(k.host))
+		if removed == 0 {
+			m.status = fmt.Sprintf("没有明确写有 %s 的记录可删除", address)
+			return
+		}
+		m.saveKnownHosts(fmt.Sprintf("已删除 %s 的 %d 条记录", address, removed))
+		return
+	}
+
+	item, ok := m.knownHostsList.SelectedItem().(KnownHostItem)
+	if !ok {
+		return
+	}
+	// 多主机共用的行只去掉当前主机，查看全部记录时删除整行
+	address := ""
+	if k.hasHost && !k.showAll {
+		address = knownhosts.Address(hostAddress(k.host))
+	}
+	if !k.file.RemoveEntry(item.entry, address) {
+		m.status = fmt.Sprintf("第 %d 行没有明确写有 %s，按 f 显示全部记录后可删除整行", item.entry.Line, address)
+		return
+	}
+	m.saveKnownHosts(fmt.Sprintf("已删除第 %d 行的记录", item.entry.Line))
+}
+
+// updateKnownHostsReplace 处理替换主机密钥的表单
+func (m Model) updateKnownHostsReplace(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := &m.knownHosts
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		k.mode = knownHostsModeList
+		m.err = nil
+		return m, nil
+	}
+
+	submit, cmd := k.form.update(msg)
+	if !submit {
+		return m, cmd
+	}
+
+	key, err := parseHostKeyInput(k.form.value(0))
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	host, port := hostAddress(k.host)
+	k.file.Replace(host, port, []ssh.PublicKey{key})
+	k.mode = knownHostsModeList
+	m.saveKnownHosts(fmt.Sprintf("已将 %s 的主机密钥替换为 %s", knownhosts.Address(host, port), ssh.FingerprintSHA256(key)))
+	return m, nil
+}
+
+// parseHostKeyInput 解析用户粘贴的主机密钥，支持公钥行和完整的 known_hosts 行
+func parseHostKeyInput(input string) (ssh.PublicKey, error) {
+	if key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(input)); err == nil {
+		return key, nil
+	}
+	if fields := strings.Fields(input); len(fields) >= 3 {
+		if key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.Join(fields[1:], " "))); err == nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("无法解析主机密钥")
+}
+
+// knownHostsView 渲染 known_hosts 视图
+func (m Model) knownHostsView() string {
+	var content strings.Builder
+	k := m.knownHosts
+
+	if k.mode == knownHostsModeFetch {
+		return m.hostKeyFetchView()
+	}
+	if k.mode == knownHostsModeReplace {
+		host, port := hostAddress(k.host)
+		content.WriteString(titleStyle.Render(fmt.Sprintf("替换 %s 的主机密钥", knownhosts.Address(host, port))))
+		content.WriteString("\n\n")
+		content.WriteString(GetFormStyle(m.width).Render(k.form.view()))
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("请通过可信渠道核对新密钥的指纹 • Enter: 替换 • Esc: 取消"))
+		return content.String()
+	}
+
+	content.WriteString(m.knownHostsList.View())
+	content.WriteString("\n")
+	if k.hasProvider {
+		content.WriteString(helpStyle.Render(fmt.Sprintf("官方指纹目录: %s（%s）", k.provider.Name, k.provider.Source)))
+		content.WriteString("\n")
+	}
+
+	switch k.mode {
+	case knownHostsModeConfirmEntry:
+		content.WriteString(warningStyle.Render("确定删除选中的记录吗？[Y] 确认 [N] 取消"))
+		content.WriteString("\n")
+	case knownHostsModeConfirmHost:
+		content.WriteString(warningStyle.Render(fmt.Sprintf("确定删除 %s 的所有记录吗？[Y] 确认 [N] 取消", knownhosts.Address(hostAddress(k.host)))))
+		content.WriteString("\n")
+	case knownHostsModeConfirmSeed:
+		content.WriteString(warningStyle.Render(fmt.Sprintf("将用 %s 公布的主机密钥替换 %s 的现有记录，确定吗？[Y] 确认 [N] 取消", k.provider.Name, knownhosts.Address(hostAddress(k.host)))))
+		content.WriteString("\n")
+	}
+	content.WriteString(m.renderMessages())
+
+	helpText := []string{
+		"d/x: 删除记录",
+		"D: 删除该主机的所有记录",
+		"R: 替换主机密钥",
+		"F: 从服务器获取",
+	}
+	if k.hasProvider {
+		helpText = append(helpText, "s: 写入官方主机密钥")
+	}
+	helpText = append(helpText, "f: 全部/仅当前主机", "Esc: 返回")
+	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
+
+	return content.String()
+}
+
+// stepResult 表示批量操作中一步的结果
+type stepResult struct {
+	ok     bool
+	detail string
+}
+
+// renderStepResults 逐行渲染操作结果，成功为 ✓，需要注意或失败为 !
+func renderStepResults(results []stepResult) string {
+	var content strings.Builder
+	for _, result := range results {
+		if result.ok {
+			content.WriteString(successStyle.Render("✓ " + result.detail))
+		} else {
+			content.WriteString(warningStyle.Render("! " + result.detail))
+		}
+		content.WriteString("\n")
+	}
+	return content.String()
+}
+
+// seedPinnedHostKeys 按官方指纹目录写入主机密钥；已有的记录全部与目录一致时不改动
+func seedPinnedHostKeys(host config.SSHHost) stepResult {
+	hostname, port := host.Target()
+	address := knownhosts.Address(hostname, port)
+	catalog, err := pinnedCatalog()
+	if err != nil {
+		return stepResult{detail: err.Error()}
+	}
+	provider, ok := catalog.Lookup(hostname, port)
+	if !ok {
+		return stepResult{detail: fmt.Sprintf("指纹目录中没有 %s，首次连接前请在 known_hosts 视图中获取并核对主机密钥", address)}
+	}
+	pubs, err := provider.PublicKeys()
+	if err != nil {
+		return stepResult{detail: err.Error()}
+	}
+	if len(pubs) == 0 {
+		return stepResult{detail: fmt.Sprintf("%s 只公布了指纹，首次连接时请在 known_hosts 视图中核对", provider.Name)}
+	}
+
+	path, err := knownhosts.DefaultPath()
+	if err != nil {
+		return stepResult{detail: err.Error()}
+	}
+	file, err := knownhosts.Load(path)
+	if err != nil {
+		return stepResult{detail: err.Error()}
+	}
+	if existing := file.Find(hostname, port); len(existing) > 0 && len(provider.Mismatches(existing)) == 0 {
+		return stepResult{ok: true, detail: fmt.Sprintf("known_hosts 中已有 %s 的官方主机密钥", address)}
+	}
+	file.Replace(hostname, port, pubs)
+	if err := file.Save(); err != nil {
+		return stepResult{detail: err.Error()}
+	}
+	return stepResult{ok: true, detail: fmt.Sprintf("已按 %s 公布的指纹写入 %s 的 %d 个主机密钥", provider.Name, address, len(pubs))}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/probe"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
@@ -18,22 +19,69 @@ const (
 	AddView
 	EditView
 	DeleteConfirmView
+	FilePickerView
+	KeysView
+	ExportView
+	HostDetailView
+	PassphraseView
+	SignView
+	AgentView
+	KnownHostsView
+	ConnTestView
+	RepoCheckView
+	RemotesView
+	WorkspaceView
+	IdentityView
+	InsteadOfView
+	WizardView
+	TemplatePickerView
+	UploadView
+	DeployKeysView
+	SigningView
+	AllowedSignersView
 )
 
 // Model 是应用的主要模型
 type Model struct {
-	sshConfig   *config.SSHConfig
-	state       ViewState
-	list        list.Model
-	form        FormModel
-	selected    int
-	err         error
-	warning     string
-	deleteIndex int
-	editIndex   int
-	isEditing   bool
-	width       int
-	height      int
+	sshConfig      *config.SSHConfig
+	state          ViewState
+	list           list.Model
+	form           FormModel
+	selected       int
+	err            error
+	warning        string
+	deleteIndex    int
+	editIndex      int
+	isEditing      bool
+	width          int
+	height         int
+	picker         filePicker
+	keyList        list.Model
+	export         exportState
+	passphrase     passphraseState
+	sign           signState
+	agentList      list.Model
+	agent          agentState
+	knownHostsList list.Model
+	knownHosts     knownHostsState
+	connTest       connTestState
+	repoCheck      repoCheckState
+	remotes        remotesState
+	workspace      workspaceState
+	identityList   list.Model
+	identity       identityState
+	insteadOfList  list.Model
+	insteadOf      insteadOfState
+	wizard         wizardState
+	templatePicker templatePicker
+	upload         uploadState
+	deployKeys     deployKeysState
+	signing        signingState
+	signersList    list.Model
+	signers        signersState
+	health         map[string]probe.Health
+	healthCheck    healthState
+	status         string
 }
 
 // FormModel 表示添加/编辑表单的模型
@@ -43,13 +91,19 @@ type FormModel struct {
 	userInput         textinput.Model
 	portInput         textinput.Model
 	identityFileInput textinput.Model
+	certificateInput  textinput.Model
 	focusIndex        int
 	inputs            []textinput.Model
+	suggestions       []string
+	suggestionIndex   int
 }
 
 // HostItem 实现 list.Item 接口
 type HostItem struct {
-	host config.SSHHost
+	host     config.SSHHost
+	warnings []string
+	health   probe.Health
+	checked  bool
 }
 
 func (h HostItem) FilterValue() string {
@@ -57,11 +111,24 @@ func (h HostItem) FilterValue() string {
 }
 
 func (h HostItem) Title() string {
+	if h.checked {
+		return h.host.Host + "  " + healthIndicator(h.health)
+	}
 	return h.host.Host
 }
 
 func (h HostItem) Description() string {
-	return fmt.Sprintf("%s@%s", h.host.User, h.host.HostName)
+	desc := fmt.Sprintf("%s@%s", h.host.User, h.host.HostName)
+	if h.checked && h.health.Account != "" {
+		desc += " • 账户 " + h.health.Account
+	}
+	if h.checked && h.health.Summary != "" {
+		desc += " • " + h.health.Summary
+	}
+	if len(h.warnings) > 0 {
+		desc += " • ⚠️ " + strings.Join(h.warnings, "；")
+	}
+	return desc
 }
 
 // NewModel 创建新的模型
@@ -73,8 +140,9 @@ func NewModel() (*Model, error) {
 
 	// 创建列表项
 	items := make([]list.Item, len(sshConfig.GetHosts()))
+	knownHosts := loadKnownHosts()
 	for i, host := range sshConfig.GetHosts() {
-		items[i] = HostItem{host: host}
+		items[i] = newHostItem(host, knownHosts)
 	}
 
 	// 创建列表模型
@@ -90,10 +158,17 @@ func NewModel() (*Model, error) {
 	form := NewFormModel()
 
 	return &Model{
-		sshConfig: sshConfig,
-		state:     ListView,
-		list:      l,
-		form:      form,
+		sshConfig:      sshConfig,
+		state:          ListView,
+		list:           l,
+		form:           form,
+		keyList:        newKeyList(),
+		agentList:      newAgentList(),
+		knownHostsList: newKnownHostsList(),
+		identityList:   newIdentityList(),
+		insteadOfList:  newInsteadOfList(),
+		signersList:    newSignersList(),
+		health:         map[string]probe.Health{},
 	}, nil
 }
 
@@ -130,8 +205,14 @@ func NewFormModel() FormModel {
 	identityFileInput.CharLimit = 200
 	identityFileInput.Width = 50
 
+	// CertificateFile 输入框
+	certificateInput := textinput.New()
+	certificateInput.Placeholder = "例如: ~/.ssh/id_ed25519-cert.pub (可留空)"
+	certificateInput.CharLimit = 200
+	certificateInput.Width = 50
+
 	// 创建inputs数组，直接引用上面创建的输入框
-	inputs := []textinput.Model{hostInput, hostnameInput, userInput, portInput, identityFileInput}
+	inputs := []textinput.Model{hostInput, hostnameInput, userInput, portInput, identityFileInput, certificateInput}
 
 	return FormModel{
 		hostInput:         hostInput,
@@ -139,6 +220,7 @@ func NewFormModel() FormModel {
 		userInput:         userInput,
 		portInput:         portInput,
 		identityFileInput: identityFileInput,
+		certificateInput:  certificateInput,
 		focusIndex:        0,
 		inputs:            inputs,
 	}
@@ -157,8 +239,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.list.SetWidth(msg.Width)
 		m.list.SetHeight(msg.Height - 3)
+		m.keyList.SetWidth(msg.Width)
+		m.keyList.SetHeight(msg.Height - 3)
+		m.agentList.SetWidth(msg.Width)
+		m.agentList.SetHeight(msg.Height - 4)
+		m.knownHostsList.SetWidth(msg.Width)
+		m.knownHostsList.SetHeight(msg.Height - 4)
+		m.identityList.SetWidth(msg.Width)
+		m.identityList.SetHeight(msg.Height - 4)
+		m.insteadOfList.SetWidth(msg.Width)
+		m.insteadOfList.SetHeight(msg.Height - 4)
+		m.signersList.SetWidth(msg.Width)
+		m.signersList.SetHeight(msg.Height - 4)
+		if m.workspace.rows != nil {
+			m.refreshWorkspaceTable()
+		}
 		return m, nil
 
+	case hostKeysFetchedMsg:
+		return m.handleHostKeysFetched(msg)
+
+	case connTestMsg:
+		return m.handleConnTestResult(msg)
+
+	case workspaceScanMsg:
+		return m.handleWorkspaceScan(msg)
+
+	case wizardKeyMsg:
+		return m.handleWizardKey(msg)
+
+	case uploadMsg:
+		return m.handleUploadResult(msg)
+
+	case deployKeysMsg:
+		return m.handleDeployKeys(msg)
+
+	case *repoCheckMsg:
+		return m.handleRepoCheckResult(msg)
+
+	case sessionExitMsg:
+		return m.handleSessionExit(msg)
+
+	case healthResultMsg:
+		return m.handleHealthResult(msg)
+
+	case healthDoneMsg:
+		return m.handleHealthDone(msg)
+
 	case tea.KeyMsg:
 		switch m.state {
 		case ListView:
@@ -169,6 +296,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateEditView(msg)
 		case DeleteConfirmView:
 			return m.updateDeleteConfirmView(msg)
+		case FilePickerView:
+			return m.updateFilePickerView(msg)
+		case KeysView:
+			return m.updateKeysView(msg)
+		case ExportView:
+			return m.updateExportView(msg)
+		case HostDetailView:
+			return m.updateHostDetailView(msg)
+		case PassphraseView:
+			return m.updatePassphraseView(msg)
+		case SignView:
+			return m.updateSignView(msg)
+		case AgentView:
+			return m.updateAgentView(msg)
+		case KnownHostsView:
+			return m.updateKnownHostsView(msg)
+		case ConnTestView:
+			return m.updateConnTestView(msg)
+		case RepoCheckView:
+			return m.updateRepoCheckView(msg)
+		case RemotesView:
+			return m.updateRemotesView(msg)
+		case WorkspaceView:
+			return m.updateWorkspaceView(msg)
+		case IdentityView:
+			return m.updateIdentityView(msg)
+		case InsteadOfView:
+			return m.updateInsteadOfView(msg)
+		case WizardView:
+			return m.updateWizardView(msg)
+		case TemplatePickerView:
+			return m.updateTemplatePickerView(msg)
+		case UploadView:
+			return m.updateUploadView(msg)
+		case DeployKeysView:
+			return m.updateDeployKeysView(msg)
+		case SigningView:
+			return m.updateSigningView(msg)
+		case AllowedSignersView:
+			return m.updateAllowedSignersView(msg)
 		}
 	}
 
@@ -177,8 +344,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // updateListView 更新列表视图
 func (m Model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
 	switch keypress := msg.String(); keypress {
 	case "ctrl+c", "q":
+		m.stopHealthCheck()
 		return m, tea.Quit
 	case "a", "n":
 		m.state = AddView
@@ -201,6 +371,53 @@ func (m Model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.state = DeleteConfirmView
 		}
 		return m, nil
+	case "enter":
+		if len(m.list.Items()) > 0 {
+			m.state = HostDetailView
+		}
+		return m, nil
+	case "p":
+		return m.openHostExport()
+	case "P":
+		return m.openHostPassphrase()
+	case "S":
+		return m.openSign()
+	case "A":
+		return m.openAgent()
+	case "H":
+		return m.openKnownHosts()
+	case "T":
+		return m.openConnTest()
+	case "L":
+		return m.openRepoCheck()
+	case "R":
+		return m.openRemotes("")
+	case "W":
+		return m.openWorkspace()
+	case "G":
+		return m.openIdentity()
+	case "I":
+		return m.openInsteadOf()
+	case "N":
+		return m.openWizard()
+	case "U":
+		return m.openUpload()
+	case "D":
+		return m.openDeployKeys()
+	case "V":
+		return m.openAllowedSigners("", ListView)
+	case "s":
+		return m.openSession()
+	case "c":
+		if m.healthCheck.running {
+			m.stopHealthCheck()
+			return m, nil
+		}
+		return m.startHealthCheck()
+	case "K":
+		m.refreshKeyList()
+		m.state = KeysView
+		return m, nil
 	}
 
 	var cmd tea.Cmd
@@ -208,6 +425,16 @@ func (m Model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// selectedHost 返回列表中当前选中的主机配置
+func (m Model) selectedHost() (config.SSHHost, bool) {
+	hosts := m.sshConfig.GetHosts()
+	index := m.list.Index()
+	if len(m.list.Items()) == 0 || index < 0 || index >= len(hosts) {
+		return config.SSHHost{}, false
+	}
+	return hosts[index], true
+}
+
 // createFormWithData 创建预填充数据的表单
 func (m Model) createFormWithData(index int) FormModel {
 	hosts := m.sshConfig.GetHosts()
@@ -224,6 +451,7 @@ func (m Model) createFormWithData(index int) FormModel {
 	form.userInput.SetValue(host.User)
 	form.portInput.SetValue(host.Port)
 	form.identityFileInput.SetValue(host.IdentityFile)
+	form.certificateInput.SetValue(host.CertificateFile)
 
 	// 同时更新inputs数组
 	form.inputs[0].SetValue(host.Host)
@@ -231,6 +459,7 @@ func (m Model) createFormWithData(index int) FormModel {
 	form.inputs[2].SetValue(host.User)
 	form.inputs[3].SetValue(host.Port)
 	form.inputs[4].SetValue(host.IdentityFile)
+	form.inputs[5].SetValue(host.CertificateFile)
 
 	return form
 }
@@ -244,9 +473,25 @@ func (m Model) updateAddView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.state = ListView
 		m.warning = ""
 		return m, nil
+	case "ctrl+f":
+		// 为 IdentityFile 或 CertificateFile 字段打开文件选择器
+		return m.openFilePicker(m.pathFieldIndex())
+	case "ctrl+t":
+		return m.openTemplatePicker()
+	case "ctrl+n":
+		m.moveSuggestion(1)
+		return m, nil
+	case "ctrl+p":
+		m.moveSuggestion(-1)
+		return m, nil
 	case "tab", "shift+tab", "enter", "up", "down":
 		s := msg.String()
 
+		// 下拉建议打开时，Tab 用于补全
+		if s == "tab" && m.acceptSuggestion() {
+			return m, nil
+		}
+
 		if s == "enter" && m.form.focusIndex == len(m.form.inputs) {
 			// 提交表单
 			return m.submitForm()
@@ -276,6 +521,7 @@ func (m Model) updateAddView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.form.inputs[i].PromptStyle = noStyle
 			m.form.inputs[i].TextStyle = noStyle
 		}
+		m.refreshSuggestions()
 
 		// 检查 IdentityFile 警告
 		if m.form.focusIndex == 4 { // IdentityFile 输入框
@@ -294,6 +540,7 @@ func (m Model) updateAddView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// 处理输入框更新
 	cmd := m.updateInputs(msg)
+	m.refreshSuggestions()
 	return m, cmd
 }
 
@@ -307,9 +554,23 @@ func (m Model) updateEditView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.warning = ""
 		m.isEditing = false
 		return m, nil
+	case "ctrl+f":
+		// 为 IdentityFile 或 CertificateFile 字段打开文件选择器
+		return m.openFilePicker(m.pathFieldIndex())
+	case "ctrl+n":
+		m.moveSuggestion(1)
+		return m, nil
+	case "ctrl+p":
+		m.moveSuggestion(-1)
+		return m, nil
 	case "tab", "shift+tab", "enter", "up", "down":
 		s := msg.String()
 
+		// 下拉建议打开时，Tab 用于补全
+		if s == "tab" && m.acceptSuggestion() {
+			return m, nil
+		}
+
 		if s == "enter" && m.form.focusIndex == len(m.form.inputs) {
 			// 提交表单
 			return m.submitForm()
@@ -339,6 +600,7 @@ func (m Model) updateEditView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.form.inputs[i].PromptStyle = noStyle
 			m.form.inputs[i].TextStyle = noStyle
 		}
+		m.refreshSuggestions()
 
 		// 检查 IdentityFile 警告
 		if m.form.focusIndex == 4 { // IdentityFile 输入框
@@ -357,6 +619,7 @@ func (m Model) updateEditView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// 处理输入框更新
 	cmd := m.updateInputs(msg)
+	m.refreshSuggestions()
 	return m, cmd
 }
 
@@ -400,21 +663,13 @@ func (m Model) updateInputs(msg tea.Msg) tea.Cmd {
 
 // submitForm 提交表单
 func (m Model) submitForm() (tea.Model, tea.Cmd) {
-	// 处理 IdentityFile 路径
-	identityFile := m.form.inputs[4].Value()
-	// 移除开头的 ?
-	if len(identityFile) > 0 && identityFile[0] == '?' {
-		identityFile = identityFile[1:]
-	}
-	// 将反斜杠转换为正斜杠
-	identityFile = strings.ReplaceAll(identityFile, "\\", "/")
-
 	host := config.SSHHost{
-		Host:         m.form.inputs[0].Value(),
-		HostName:     m.form.inputs[1].Value(),
-		User:         m.form.inputs[2].Value(),
-		Port:         m.form.inputs[3].Value(),
-		IdentityFile: identityFile,
+		Host:            m.form.inputs[0].Value(),
+		HostName:        m.form.inputs[1].Value(),
+		User:            m.form.inputs[2].Value(),
+		Port:            m.form.inputs[3].Value(),
+		IdentityFile:    normalizePath(m.form.inputs[4].Value()),
+		CertificateFile: normalizePath(m.form.inputs[5].Value()),
 	}
 
 	// 验证必填字段
@@ -447,11 +702,32 @@ func (m Model) submitForm() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// normalizePath 处理表单中输入的文件路径
+func normalizePath(path string) string {
+	// 移除开头的 ?
+	if len(path) > 0 && path[0] == '?' {
+		path = path[1:]
+	}
+	// 将反斜杠转换为正斜杠
+	return strings.ReplaceAll(path, "\\", "/")
+}
+
+// pathFieldIndex 返回文件选择器应写回的输入框，未聚焦在路径字段时默认为 IdentityFile
+func (m Model) pathFieldIndex() int {
+	if m.form.focusIndex == 5 {
+		return 5
+	}
+	return 4
+}
+
 // refreshList 刷新列表
 func (m *Model) refreshList() {
 	items := make([]list.Item, len(m.sshConfig.GetHosts()))
+	knownHosts := loadKnownHosts()
 	for i, host := range m.sshConfig.GetHosts() {
-		items[i] = HostItem{host: host}
+		item := newHostItem(host, knownHosts)
+		item.health, item.checked = m.health[host.Host]
+		items[i] = item
 	}
 	m.list.SetItems(items)
-}
\ No newline at end of file
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// passphraseState 保存口令设置视图的状态
+type passphraseState struct {
+	keyPath     string
+	encrypted   bool
+	form        inputForm
+	returnState ViewState
+}
+
+// newPasswordInput 创建掩码显示的口令输入框
+func newPasswordInput(placeholder string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.EchoMode = textinput.EchoPassword
+	input.EchoCharacter = '•'
+	input.CharLimit = 128
+	input.Width = 30
+	return input
+}
+
+// openPassphrase 打开私钥口令设置视图
+func (m Model) openPassphrase(keyPath string) (tea.Model, tea.Cmd) {
+	info, err := keys.Inspect(keyPath)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	switch info.Format {
+	case keys.FormatOpenSSH, keys.FormatPEM, keys.FormatPKCS8:
+	default:
+		m.err = fmt.Errorf("不支持修改 %s 格式密钥的口令", info.Format)
+		return m, nil
+	}
+	if info.Format == keys.FormatPKCS8 && info.Encrypted {
+		m.err = keys.ErrEncryptedPKCS8
+		return m, nil
+	}
+
+	var labels []string
+	var inputs []textinput.Model
+	if info.Encrypted {
+		labels = append(labels, "当前口令:")
+		inputs = append(inputs, newPasswordInput("当前口令"))
+	}
+	labels = append(labels, "新口令:", "确认口令:")
+	inputs = append(inputs,
+		newPasswordInput("留空则移除口令"),
+		newPasswordInput("再次输入新口令"),
+	)
+
+	m.passphrase = passphraseState{
+		keyPath:     keyPath,
+		encrypted:   info.Encrypted,
+		form:        newInputForm(labels, inputs),
+		returnState: m.state,
+	}
+	m.err = nil
+	m.state = PassphraseView
+	return m, textinput.Blink
+}
+
+// openHostPassphrase 为选中主机的 IdentityFile 打开口令设置视图
+func (m Model) openHostPassphrase() (tea.Model, tea.Cmd) {
+	host, ok := m.selectedHost()
+	if !ok {
+		return m, nil
+	}
+	if host.IdentityFile == "" {
+		m.err = fmt.Errorf("主机 %s 未配置 IdentityFile", host.Host)
+		return m, nil
+	}
+	return m.openPassphrase(config.ExpandPath(host.IdentityFile))
+}
+
+// updatePassphraseView 更新口令设置视图
+func (m Model) updatePassphraseView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = m.passphrase.returnState
+		m.err = nil
+		return m, nil
+	}
+
+	submit, cmd := m.passphrase.form.update(msg)
+	if submit {
+		return m.submitPassphrase()
+	}
+	return m, cmd
+}
+
+// submitPassphrase 校验输入并修改私钥口令
+func (m Model) submitPassphrase() (tea.Model, tea.Cmd) {
+	p := m.passphrase
+
+	var oldPassphrase []byte
+	inputs := p.form.inputs
+	if p.encrypted {
+		oldPassphrase = []byte(inputs[0].Value())
+		inputs = inputs[1:]
+	}
+	newPassphrase := []byte(inputs[0].Value())
+	if !bytes.Equal(newPassphrase, []byte(inputs[1].Value())) {
+		m.err = fmt.Errorf("两次输入的新口令不一致")
+		return m, nil
+	}
+	if len(newPassphrase) == 0 && !p.encrypted {
+		m.err = fmt.Errorf("该私钥未加密，请输入新口令")
+		return m, nil
+	}
+
+	backup, err := keys.ChangePassphrase(p.keyPath, oldPassphrase, newPassphrase)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	switch {
+	case len(newPassphrase) == 0:
+		m.status = "已移除口令"
+	case p.encrypted:
+		m.status = "已修改口令"
+	default:
+		m.status = "已设置口令"
+	}
+	m.status += fmt.Sprintf("，原文件备份为 %s", config.ContractPath(backup))
+	m.err = nil
+	m.state = p.returnState
+	m.refreshList()
+	if m.state == KeysView {
+		m.refreshKeyList()
+	}
+	return m, nil
+}
+
+// passphraseView 渲染口令设置视图
+func (m Model) passphraseView() string {
+	var content strings.Builder
+
+	content.WriteString(titleStyle.Render("设置私钥口令"))
+	content.WriteString("\n\n")
+
+	var form strings.Builder
+	form.WriteString(fmt.Sprintf("私钥: %s\n\n", config.ContractPath(m.passphrase.keyPath)))
+	form.WriteString(m.passphrase.form.view())
+	content.WriteString(GetFormStyle(m.width).Render(form.String()))
+	content.WriteString("\n")
+
+	content.WriteString(m.renderMessages())
+
+	helpText := []string{
+		"Tab: 下一个字段",
+		"Enter: 确认（在最后一个字段时）",
+		"Esc: 取消",
+	}
+	content.WriteString(helpStyle.Render("原私钥会先备份，然后以 OpenSSH 格式重写"))
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
+
+	return content.String()
+}
@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/gitconfig"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// remoteOption 表示一个远程地址可以改写成的目标
+type remoteOption struct {
+	label string
+	url   string
+}
+
+// remoteRow 表示仓库配置中的一个远程地址及其可选的改写方式，第一个选项始终为保持不变
+type remoteRow struct {
+	remote  gitconfig.RemoteURL
+	note    string
+	options []remoteOption
+	choice  int
+}
+
+// changed 判断是否选择了改写
+func (r remoteRow) changed() bool {
+	return r.choice > 0
+}
+
+// remotesState 保存远程地址改写视图的状态
+type remotesState struct {
+	form        inputForm
+	file        *gitconfig.File
+	rows        []remoteRow
+	cursor      int
+	confirm     bool
+	fixed       bool
+	returnState ViewState
+}
+
+// openRemotes 打开远程地址改写视图；repo 为空时填入当前目录等待输入，否则直接读取该仓库
+func (m Model) openRemotes(repo string) (tea.Model, tea.Cmd) {
+	value := repo
+	if value == "" {
+		dir, _ := os.Getwd()
+		value = dir
+	}
+	input := newTextInput("仓库目录", config.ContractPath(value), 50)
+	input.CursorEnd()
+	m.remotes = remotesState{
+		form:        newInputForm([]string{"仓库:"}, []textinput.Model{input}),
+		fixed:       repo != "",
+		returnState: m.state,
+	}
+	m.err = nil
+	m.state = RemotesView
+	if repo != "" {
+		file, rows, err := m.loadRemotes(repo)
+		if err != nil {
+			m.err = err
+		} else {
+			m.remotes.file, m.remotes.rows = file, rows
+		}
+	}
+	return m, textinput.Blink
+}
+
+// loadRemotes 读取仓库配置并为每个远程地址计算可选的改写方式
+func (m Model) loadRemotes(repo string) (*gitconfig.File, []remoteRow, error) {
+	path, err := gitconfig.RepoConfigPath(config.ExpandPath(repo))
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := gitconfig.Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rows []remoteRow
+	for _, remote := range file.RemoteURLs() {
+		rows = append(rows, m.remoteRow(remote))
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("%s 中没有配置远程仓库", config.ContractPath(path))
+	}
+	return file, rows, nil
+}
+
+// remoteRow 判断远程地址是否已使用别名，或有哪些别名连接到同一主机。
+// 只有一个别名匹配时默认选中它，多个别名匹配时需要手动选择。
+func (m Model) remoteRow(remote gitconfig.RemoteURL) remoteRow {
+	row := remoteRow{remote: remote, options: []remoteOption{{label: "保持不变", url: remote.URL}}}
+	parsed, err := gitconfig.ParseRemote(remote.URL)
+	if err != nil {
+		row.note = "不是 SSH 地址"
+		return row
+	}
+
+	if host, ok := m.sshConfig.FindHost(parsed.Host); ok && host.HostName != "" && host.HostName != host.Host {
+		row.note = fmt.Sprintf("已使用别名 %s", host.Host)
+		row.options = append(row.options, remoteOption{
+			label: fmt.Sprintf("还原为 %s", host.HostName),
+			url:   fromAlias(parsed, host).String(),
+		})
+		return row
+	}
+
+	matches := m.sshConfig.MatchHosts(parsed.Host, parsed.Port, parsed.User)
+	for _, host := range matches {
+		if host.Host == parsed.Host {
+			continue
+		}
+		row.options = append(row.options, remoteOption{
+			label: fmt.Sprintf("使用别名 %s", host.Host),
+			url:   toAlias(parsed, host).String(),
+		})
+	}
+	switch len(row.options) - 1 {
+	case 0:
+		row.note = "没有匹配的别名"
+	case 1:
+		row.choice = 1
+	default:
+		row.note = fmt.Sprintf("%d 个别名匹配，请选择", len(row.options)-1)
+	}
+	return row
+}
+
+// toAlias 将地址改写为使用别名；别名设置了 User 时使用它，端口由别名配置决定
+func toAlias(remote gitconfig.Remote, host config.SSHHost) gitconfig.Remote {
+	user := remote.User
+	if host.User != "" {
+		user = host.User
+	}
+	return gitconfig.Remote{User: user, Host: host.Host, Path: remote.Path}
+}
+
+// fromAlias 将使用别名的地址还原为实际的主机名、端口和用户
+func fromAlias(remote gitconfig.Remote, host config.SSHHost) gitconfig.Remote {
+	hostname, port := host.Target()
+	if port == config.DefaultPort {
+		port = ""
+	}
+	user := remote.User
+	if user == "" {
+		user = host.User
+	}
+	return gitconfig.Remote{User: user, Host: hostname, Port: port, Path: remote.Path, URL: remote.URL}
+}
+
+// applyRemotes 将选中的改写写入仓库配置
+func (m Model) applyRemotes() (int, error) {
+	r := m.remotes
+	changed := 0
+	for _, row := range r.rows {
+		if !row.changed() {
+			continue
+		}
+		changed += r.file.ReplaceValue("remote", row.remote.Name, row.remote.Key, row.remote.URL, row.options[row.choice].url)
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, r.file.Save()
+}
+
+// updateRemotesView 更新远程地址改写视图
+func (m Model) updateRemotesView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := &m.remotes
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	// 输入仓库目录
+	if r.file == nil {
+		if msg.String() == "esc" {
+			m.state = r.returnState
+			m.err = nil
+			return m, nil
+		}
+		submit, cmd := r.form.update(msg)
+		if !submit {
+			return m, cmd
+		}
+		file, rows, err := m.loadRemotes(r.form.value(0))
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		r.file, r.rows, r.cursor = file, rows, 0
+		m.err = nil
+		return m, nil
+	}
+
+	if r.confirm {
+		switch msg.String() {
+		case "y", "Y":
+			r.confirm = false
+			changed, err := m.applyRemotes()
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.status = fmt.Sprintf("已改写 %d 个远程地址，原配置备份为 config.bak", changed)
+			m.err = nil
+			// 重新读取，显示写入后的状态
+			file, rows, err := m.loadRemotes(r.form.value(0))
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			// 写入后不再自动选中改写，避免刚还原的地址又显示为待改写
+			for i := range rows {
+				rows[i].choice = 0
+			}
+			r.file, r.rows = file, rows
+		case "n", "N", "esc":
+			r.confirm = false
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		// 从工作区扫描等处直接打开时返回来源视图
+		if r.fixed {
+			m.state = r.returnState
+			m.status = ""
+			m.err = nil
+			return m, nil
+		}
+		r.file, r.rows = nil, nil
+		m.status = ""
+		m.err = nil
+		return m, textinput.Blink
+	case "up", "k":
+		if r.cursor > 0 {
+			r.cursor--
+		}
+	case "down", "j":
+		if r.cursor < len(r.rows)-1 {
+			r.cursor++
+		}
+	case "right", "l", "tab", " ":
+		row := &r.rows[r.cursor]
+		row.choice = (row.choice + 1) % len(row.options)
+	case "left", "h", "shift+tab":
+		row := &r.rows[r.cursor]
+		row.choice = (row.choice + len(row.options) - 1) % len(row.options)
+	case "w":
+		for _, row := range r.rows {
+			if row.changed() {
+				r.confirm = true
+				m.status = ""
+				return m, nil
+			}
+		}
+		m.err = fmt.Errorf("没有需要改写的远程地址")
+	}
+	return m, nil
+}
+
+// remotesView 渲染远程地址改写视图
+func (m Model) remotesView() string {
+	var content strings.Builder
+	r := m.remotes
+
+	content.WriteString(titleStyle.Render("改写远程地址"))
+	content.WriteString("\n\n")
+
+	if r.file == nil {
+		content.WriteString(GetFormStyle(m.width).Render(r.form.view()))
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("Enter: 读取远程仓库 • Esc: 返回"))
+		return content.String()
+	}
+
+	content.WriteString(fmt.Sprintf("%s\n\n", config.ContractPath(r.file.Path)))
+	for i, row := range r.rows {
+		name := fmt.Sprintf("%s (%s)", row.remote.Name, row.remote.Key)
+		if i == r.cursor {
+			content.WriteString(focusedStyle.Render("> " + name))
+		} else {
+			content.WriteString("  " + name)
+		}
+		if row.note != "" {
+			content.WriteString(" " + helpStyle.Render(row.note))
+		}
+		content.WriteString("\n")
+
+		if row.changed() {
+			option := row.options[row.choice]
+			content.WriteString(fmt.Sprintf("    - %s\n", row.remote.URL))
+			content.WriteString(successStyle.Render(fmt.Sprintf("    + %s", option.url)))
+			content.WriteString(helpStyle.Render(fmt.Sprintf("  [%s]", option.label)))
+		} else {
+			content.WriteString(fmt.Sprintf("      %s", row.remote.URL))
+			if len(row.options) > 1 {
+				content.WriteString(helpStyle.Render(fmt.Sprintf("  [保持不变，共 %d 个选项]", len(row.options))))
+			}
+		}
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(m.renderMessages())
+
+	if r.confirm {
+		count := 0
+		for _, row := range r.rows {
+			if row.changed() {
+				count++
+			}
+		}
+		content.WriteString(warningStyle.Render(fmt.Sprintf("确定改写以上 %d 个远程地址吗？原配置将备份为 config.bak", count)))
+		content.WriteString("\n\n")
+		content.WriteString(helpStyle.Render("y: 确认 • n/Esc: 取消"))
+		return content.String()
+	}
+
+	back := "Esc: 重新选择仓库"
+	if r.fixed {
+		back = "Esc: 返回"
+	}
+	content.WriteString(helpStyle.Render("↑/↓: 选择 • ←/→/空格: 切换改写方式 • w: 写入 • " + back))
+	return content.String()
+}
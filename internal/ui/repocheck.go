@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/agent"
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/gitconfig"
+	"github.com/allanpk716/git_ssh_tui/internal/probe"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// repoCheckMsg 仓库访问检查完成后的消息，run 标识发起检查的那一次运行
+type repoCheckMsg struct {
+	run    int
+	remote gitconfig.Remote
+	result *probe.Result
+	info   *probe.RepoInfo
+	err    error
+}
+
+// repoCheckState 保存仓库访问检查视图的状态
+type repoCheckState struct {
+	form        inputForm
+	running     bool
+	cancel      context.CancelFunc
+	done        *repoCheckMsg
+	returnState ViewState
+	// run 在每次开始检查时递增，用于丢弃已取消的检查迟到的结果
+	run int
+}
+
+// openRepoCheck 打开仓库访问检查视图，默认填入选中主机的别名
+func (m Model) openRepoCheck() (tea.Model, tea.Cmd) {
+	value := ""
+	if host, ok := m.selectedHost(); ok && !host.IsPattern() {
+		value = host.Host + ":"
+	}
+	input := newTextInput("别名:组织/仓库.git", value, 50)
+	input.CursorEnd()
+	m.repoCheck = repoCheckState{
+		form:        newInputForm([]string{"仓库:"}, []textinput.Model{input}),
+		returnState: m.state,
+		run:         m.repoCheck.run,
+	}
+	m.err = nil
+	m.state = RepoCheckView
+	return m, textinput.Blink
+}
+
+// remoteHost 按别名查找主机配置，找不到时把别名当作主机名；地址中的用户和端口优先
+func (m Model) remoteHost(remote gitconfig.Remote) config.SSHHost {
+	host := config.SSHHost{Host: remote.Host, HostName: remote.Host}
+	for _, h := range m.sshConfig.GetHosts() {
+		if h.Host == remote.Host {
+			host = h
+			break
+		}
+	}
+	if remote.User != "" {
+		host.User = remote.User
+	}
+	if remote.Port != "" {
+		host.Port = remote.Port
+	}
+	return host
+}
+
+// runRepoCheck 在后台通过主机配置连接服务器并读取仓库的引用公告
+func runRepoCheck(ctx context.Context, run int, remote gitconfig.Remote, target probe.Target) tea.Cmd {
+	return func() tea.Msg {
+		if client, err := agent.Dial(); err == nil {
+			defer client.Close()
+			target.Agent = client.Agent()
+		}
+		msg := &repoCheckMsg{run: run, remote: remote}
+		client, result := probe.Connect(ctx, target)
+		msg.result = result
+		if client == nil {
+			return msg
+		}
+		defer client.Close()
+		msg.info, msg.err = probe.LsRemote(ctx, client, remote.Path, probe.DefaultTimeout)
+		return msg
+	}
+}
+
+// handleRepoCheckResult 处理检查结果；用户已取消或结果来自之前取消的检查时丢弃
+func (m Model) handleRepoCheckResult(msg *repoCheckMsg) (tea.Model, tea.Cmd) {
+	if m.state != RepoCheckView || !m.repoCheck.running || msg.run != m.repoCheck.run {
+		return m, nil
+	}
+	m.repoCheck.running = false
+	m.repoCheck.cancel()
+	m.repoCheck.done = msg
+	return m, nil
+}
+
+// updateRepoCheckView 更新仓库访问检查视图
+func (m Model) updateRepoCheckView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := &m.repoCheck
+	switch msg.String() {
+	case "ctrl+c":
+		if r.running {
+			r.cancel()
+		}
+		return m, tea.Quit
+	case "esc":
+		if r.running {
+			r.cancel()
+			r.running = false
+			return m, nil
+		}
+		m.state = r.returnState
+		m.err = nil
+		return m, nil
+	}
+	if r.running {
+		return m, nil
+	}
+
+	submit, cmd := r.form.update(msg)
+	if !submit {
+		return m, cmd
+	}
+
+	remote, err := gitconfig.ParseRemote(r.form.value(0))
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.running = true
+	r.cancel = cancel
+	r.done = nil
+	r.run++
+	m.err = nil
+	return m, runRepoCheck(ctx, r.run, remote, hostTarget(m.remoteHost(remote)))
+}
+
+// repoCheckView 渲染仓库访问检查视图
+func (m Model) repoCheckView() string {
+	var content strings.Builder
+	r := m.repoCheck
+
+	content.WriteString(titleStyle.Render("检查仓库访问"))
+	content.WriteString("\n\n")
+	content.WriteString(GetFormStyle(m.width).Render(r.form.view()))
+	content.WriteString("\n")
+	content.WriteString(m.renderMessages())
+
+	if r.running {
+		content.WriteString("正在读取仓库引用...\n\n")
+		content.WriteString(helpStyle.Render("Esc: 取消"))
+		return content.String()
+	}
+
+	if d := r.done; d != nil {
+		if !d.result.OK() {
+			// 连接阶段失败时显示出错的步骤
+			for _, step := range d.result.Steps {
+				if step.Status == probe.StepFailed {
+					content.WriteString(errorStyle.Render(fmt.Sprintf("✗ %s: %s", step.Name, step.Detail)))
+					content.WriteString("\n")
+				}
+			}
+		} else if d.err != nil {
+			content.WriteString(errorStyle.Render(fmt.Sprintf("✗ 无法读取 %s", d.remote)))
+			content.WriteString("\n")
+			content.WriteString(d.err.Error())
+			content.WriteString("\n")
+		} else {
+			content.WriteString(successStyle.Render(fmt.Sprintf("✓ 可以读取 %s", d.remote)))
+			content.WriteString("\n")
+			if d.info.DefaultBranch != "" {
+				content.WriteString(fmt.Sprintf("默认分支: %s\n", d.info.DefaultBranch))
+			}
+			if d.info.Refs == 0 {
+				content.WriteString("空仓库，还没有任何引用\n")
+			} else {
+				content.WriteString(fmt.Sprintf("引用: %d 个（分支 %d，标签 %d）\n", d.info.Refs, d.info.Branches, d.info.Tags))
+			}
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString(helpStyle.Render("Enter: 检查 • Esc: 返回"))
+	return content.String()
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sessionExitMsg 会话命令退出后的消息
+type sessionExitMsg struct {
+	command string
+	err     error
+}
+
+// openSession 暂停界面，在当前终端中按会话命令模板连接选中主机，退出后返回列表
+func (m Model) openSession() (tea.Model, tea.Cmd) {
+	host, ok := m.selectedHost()
+	if !ok {
+		return m, nil
+	}
+	if host.IsPattern() {
+		m.err = fmt.Errorf("%s 是通配符条目，无法直接连接", host.Host)
+		return m, nil
+	}
+
+	settings, err := config.LoadSettings()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	args, err := settings.SSHCommandArgs(host)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	command := strings.Join(args, " ")
+	cmd := exec.Command(args[0], args[1:]...)
+	m.err = nil
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return sessionExitMsg{command: command, err: err}
+	})
+}
+
+// handleSessionExit 显示会话命令的退出状态
+func (m Model) handleSessionExit(msg sessionExitMsg) (tea.Model, tea.Cmd) {
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(msg.err, &exitErr):
+		m.err = fmt.Errorf("%s 已退出，状态码 %d", msg.command, exitErr.ExitCode())
+	case msg.err != nil:
+		m.err = fmt.Errorf("无法执行 %s: %w", msg.command, msg.err)
+	default:
+		m.err = nil
+		m.status = fmt.Sprintf("%s 已退出，状态码 0", msg.command)
+	}
+	return m, nil
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// 签发证书表单中各输入框的索引
+const (
+	signCAKey = iota
+	signCAPassphrase
+	signUserKey
+	signPrincipals
+	signValidity
+	signKeyID
+	signExtensions
+)
+
+// signState 保存证书签发视图的状态
+type signState struct {
+	hostIndex   int
+	form        inputForm
+	returnState ViewState
+}
+
+// openSign 为选中主机的 IdentityFile 打开证书签发视图
+func (m Model) openSign() (tea.Model, tea.Cmd) {
+	host, ok := m.selectedHost()
+	if !ok {
+		return m, nil
+	}
+	if host.IdentityFile == "" {
+		m.err = fmt.Errorf("主机 %s 未配置 IdentityFile", host.Host)
+		return m, nil
+	}
+
+	principal := host.User
+	if current, err := user.Current(); err == nil {
+		principal = current.Username
+	}
+
+	labels := []string{"CA 私钥:", "CA 口令:", "用户密钥:", "Principals:", "有效期:", "Key ID:", "扩展:"}
+	inputs := []textinput.Model{
+		newTextInput("例如: ~/.ssh/lab_ca", "", 50),
+		newPasswordInput("CA 私钥未加密可留空"),
+		newTextInput("", host.IdentityFile, 50),
+		newTextInput("多个用逗号分隔", principal, 50),
+		newTextInput("例如: +52w、+30d、forever", "+52w", 20),
+		newTextInput("", fmt.Sprintf("%s@%s", principal, host.Host), 50),
+		newTextInput("多个用逗号分隔", strings.Join(keys.DefaultExtensions, ","), 50),
+	}
+
+	m.sign = signState{
+		hostIndex:   m.list.Index(),
+		form:        newInputForm(labels, inputs),
+		returnState: m.state,
+	}
+	m.err = nil
+	m.state = SignView
+	return m, textinput.Blink
+}
+
+// updateSignView 更新证书签发视图
+func (m Model) updateSignView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = m.sign.returnState
+		m.err = nil
+		return m, nil
+	}
+
+	submit, cmd := m.sign.form.update(msg)
+	if submit {
+		return m.submitSign()
+	}
+	return m, cmd
+}
+
+// submitSign 签发证书并写入主机的 CertificateFile
+func (m Model) submitSign() (tea.Model, tea.Cmd) {
+	f := m.sign.form
+	now := time.Now()
+
+	validBefore, err := keys.ParseValidity(f.value(signValidity), now)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	opts := keys.SignOptions{
+		KeyID:       f.value(signKeyID),
+		Principals:  splitList(f.value(signPrincipals)),
+		ValidAfter:  now.Add(-time.Minute), // 容忍少量时钟偏差
+		ValidBefore: validBefore,
+		Extensions:  splitList(f.value(signExtensions)),
+	}
+
+	ca, err := keys.LoadSigner(config.ExpandPath(f.value(signCAKey)), []byte(f.inputs[signCAPassphrase].Value()))
+	if err != nil {
+		m.err = fmt.Errorf("CA 私钥: %w", err)
+		return m, nil
+	}
+	certPath, err := keys.SignKeyFile(ca, config.ExpandPath(f.value(signUserKey)), opts)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	// 写回主机配置
+	hosts := m.sshConfig.GetHosts()
+	if m.sign.hostIndex >= 0 && m.sign.hostIndex < len(hosts) {
+		host := hosts[m.sign.hostIndex]
+		host.CertificateFile = config.ContractPath(certPath)
+		if err := m.sshConfig.UpdateHost(m.sign.hostIndex, host); err != nil {
+			m.err = err
+			return m, nil
+		}
+		if err := m.sshConfig.Save(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.refreshList()
+	}
+
+	m.status = fmt.Sprintf("已签发证书 %s", config.ContractPath(certPath))
+	m.err = nil
+	m.state = m.sign.returnState
+	return m, nil
+}
+
+// splitList 将逗号分隔的字符串拆分为去除空白的列表
+func splitList(s string) []string {
+	var result []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// signView 渲染证书签发视图
+func (m Model) signView() string {
+	var content strings.Builder
+
+	content.WriteString(titleStyle.Render("使用本地 CA 签发用户证书"))
+	content.WriteString("\n\n")
+	content.WriteString(GetFormStyle(m.width).Render(m.sign.form.view()))
+	content.WriteString("\n")
+
+	content.WriteString(m.renderMessages())
+
+	content.WriteString(helpStyle.Render("证书将写入 <用户密钥>-cert.pub，并设置为该主机的 CertificateFile"))
+	content.WriteString("\n")
+	helpText := []string{
+		"Tab: 下一个字段",
+		"Enter: 签发（在最后一个字段时）",
+		"Esc: 取消",
+	}
+	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
+
+	return content.String()
+}
@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/allowedsigners"
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/gitconfig"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+)
+
+// 签名设置表单中各字段的位置
+const (
+	signingFieldDir = iota
+	signingFieldEmail
+	signingFieldSigners
+)
+
+// signingState 保存提交签名设置视图的状态
+type signingState struct {
+	info     *keys.KeyInfo
+	pub      ssh.PublicKey
+	global   *gitconfig.File
+	form     inputForm
+	autoSign bool
+	rules    []gitconfig.IdentityRule
+	// current 为全局（键为空）和各 includeIf 目录已有的签名设置
+	current map[string]gitconfig.SigningSettings
+}
+
+// openSigning 为密钥清单中选中的密钥打开提交签名设置视图
+func (m Model) openSigning(info *keys.KeyInfo) (tea.Model, tea.Cmd) {
+	if info.Format == keys.FormatPublic || info.Format == keys.FormatPuTTY {
+		m.err = fmt.Errorf("%s 不是 OpenSSH 可用的私钥", config.ContractPath(info.Path))
+		return m, nil
+	}
+	pub, _, err := keys.PublicKey(info.Path)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	path, err := gitconfig.GlobalConfigPath()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	global, err := gitconfig.Load(path)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.signing = signingState{info: info, pub: pub, global: global, autoSign: true}
+	m.loadSigningSettings()
+
+	signers := m.signing.current[""].AllowedSigners
+	if signers == "" {
+		if path, err := allowedsigners.DefaultPath(); err == nil {
+			signers = config.ContractPath(path)
+		}
+	}
+	m.signing.form = newInputForm(
+		[]string{"目录:", "签名邮箱:", "签名者文件:"},
+		[]textinput.Model{
+			newTextInput("留空为全局设置；例如: ~/work/", "", 40),
+			newTextInput("留空使用该目录或全局的 user.email", "", 40),
+			newTextInput("例如: ~/.ssh/allowed_signers", signers, 40),
+		},
+	)
+	m.err = nil
+	m.status = ""
+	m.state = SigningView
+	return m, textinput.Blink
+}
+
+// loadSigningSettings 读取全局配置和各 includeIf 引入文件中的签名设置
+func (m *Model) loadSigningSettings() {
+	s := &m.signing
+	s.current = map[string]gitconfig.SigningSettings{"": s.global.Signing()}
+	s.rules = s.global.IdentityRules()
+	for _, rule := range s.rules {
+		if included, err := gitconfig.Load(s.global.IncludePath(rule.Include)); err == nil {
+			s.current[rule.Condition] = included.Signing()
+		}
+	}
+}
+
+// rule 返回目录条件对应的 includeIf 规则，gitdir 与 gitdir/i 视为同一目录
+func (s signingState) rule(condition string) (gitconfig.IdentityRule, bool) {
+	dir := strings.TrimPrefix(condition, "gitdir:")
+	for _, rule := range s.rules {
+		if rule.GitDir() == dir {
+			return rule, true
+		}
+	}
+	return gitconfig.IdentityRule{}, false
+}
+
+// signingTarget 返回表单中目录对应的 includeIf 条件，全局设置时为空
+func (s signingState) signingTarget() string {
+	dir := s.form.value(signingFieldDir)
+	if dir == "" {
+		return ""
+	}
+	condition := gitconfig.GitDirCondition(dir)
+	if rule, ok := s.rule(condition); ok {
+		return rule.Condition
+	}
+	return condition
+}
+
+// signingTargetFile 返回要写入签名设置的配置文件：全局配置，或目录 includeIf 引入的文件。
+// 目录还没有规则时新建 ~/.gitconfig-<目录名> 并登记到全局配置
+func (m Model) signingTargetFile() (*gitconfig.File, bool, error) {
+	s := m.signing
+	condition := s.signingTarget()
+	if condition == "" {
+		return s.global, false, nil
+	}
+	include := "~/.gitconfig-" + filepath.Base(strings.TrimSuffix(filepath.ToSlash(s.form.value(signingFieldDir)), "/"))
+	if rule, ok := s.rule(condition); ok {
+		include = rule.Include
+	}
+	file, err := gitconfig.Load(s.global.IncludePath(include))
+	if err != nil {
+		return nil, false, err
+	}
+	return file, s.global.EnsureInclude(condition, include), nil
+}
+
+// signingEmail 返回签名者主体：表单中的邮箱，否则为目录规则或全局配置中的 user.email
+func (s signingState) signingEmail() string {
+	if email := s.form.value(signingFieldEmail); email != "" {
+		return email
+	}
+	if condition := s.signingTarget(); condition != "" {
+		if rule, ok := s.rule(condition); ok && rule.Email != "" {
+			return rule.Email
+		}
+	}
+	email, _ := s.global.Get("user", "", "email")
+	return email
+}
+
+// applySigning 写入签名设置，并把密钥以 git 命名空间加入 allowed_signers
+func (m Model) applySigning() (tea.Model, tea.Cmd) {
+	s := &m.signing
+	email := s.signingEmail()
+	if email == "" {
+		m.err = fmt.Errorf("请填写签名邮箱（git 用它在 allowed_signers 中查找签名者）")
+		return m, nil
+	}
+	signersPath := s.form.value(signingFieldSigners)
+
+	// 先更新 allowed_signers，确保启用签名后立即能验证自己的提交
+	added := false
+	if signersPath != "" {
+		signers, err := allowedsigners.Load(config.ExpandPath(signersPath))
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		if _, ok := signers.Find(email, s.pub); !ok {
+			signers.Add(allowedsigners.Entry{Principals: []string{email}, Namespaces: []string{"git"}, Key: s.pub})
+			if err := signers.Save(); err != nil {
+				m.err = err
+				return m, nil
+			}
+			added = true
+		}
+	}
+
+	file, includeAdded, err := m.signingTargetFile()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	file.SetSSHSigning(gitconfig.SigningSettings{
+		Key:            config.ContractPath(s.info.Path),
+		AllowedSigners: signersPath,
+		CommitSign:     s.autoSign,
+		TagSign:        s.autoSign,
+	})
+	if err := file.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if includeAdded {
+		if err := s.global.Save(); err != nil {
+			m.err = err
+			return m, nil
+		}
+	}
+
+	m.loadSigningSettings()
+	m.err = nil
+	m.status = fmt.Sprintf("已在 %s 中启用 SSH 签名", config.ContractPath(file.Path))
+	if added {
+		m.status += fmt.Sprintf("，并把 %s 加入 %s", email, signersPath)
+	}
+	return m, nil
+}
+
+// removeSigning 删除目标配置文件中的签名设置，allowed_signers 保持不变
+func (m Model) removeSigning() (tea.Model, tea.Cmd) {
+	s := &m.signing
+	condition := s.signingTarget()
+	if s.current[condition].Empty() {
+		m.status = ""
+		m.err = fmt.Errorf("没有可移除的签名设置")
+		return m, nil
+	}
+	file := s.global
+	if condition != "" {
+		rule, _ := s.rule(condition)
+		loaded, err := gitconfig.Load(s.global.IncludePath(rule.Include))
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		file = loaded
+	}
+	file.UnsetSigning()
+	if err := file.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.loadSigningSettings()
+	m.err = nil
+	m.status = fmt.Sprintf("已从 %s 中移除签名设置", config.ContractPath(file.Path))
+	return m, nil
+}
+
+// updateSigningView 更新提交签名设置视图
+func (m Model) updateSigningView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.signing
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = KeysView
+		m.err = nil
+		m.status = ""
+		return m, nil
+	case "ctrl+g":
+		s.autoSign = !s.autoSign
+		return m, nil
+	case "ctrl+x":
+		return m.removeSigning()
+	case "ctrl+o":
+		return m.openAllowedSigners(s.form.value(signingFieldSigners), SigningView)
+	}
+
+	submit, cmd := s.form.update(msg)
+	if !submit {
+		return m, cmd
+	}
+	return m.applySigning()
+}
+
+// describeSigning 返回签名设置的一行说明
+func describeSigning(settings gitconfig.SigningSettings) string {
+	if settings.Empty() {
+		return "未设置"
+	}
+	var parts []string
+	if settings.Format != "" {
+		parts = append(parts, "gpg.format="+settings.Format)
+	}
+	if settings.Key != "" {
+		parts = append(parts, "signingKey="+settings.Key)
+	}
+	if settings.CommitSign || settings.TagSign {
+		parts = append(parts, "自动签名")
+	}
+	return strings.Join(parts, " • ")
+}
+
+// signingView 渲染提交签名设置视图
+func (m Model) signingView() string {
+	var content strings.Builder
+	s := m.signing
+
+	content.WriteString(titleStyle.Render("提交签名: " + config.ContractPath(s.info.Path)))
+	content.WriteString("\n\n")
+	content.WriteString(fmt.Sprintf("%s %s\n", keys.KeyTypeName(s.pub.Type()), ssh.FingerprintSHA256(s.pub)))
+	content.WriteString(fmt.Sprintf("全局 (%s): %s\n", config.ContractPath(s.global.Path), describeSigning(s.current[""])))
+	for _, rule := range s.rules {
+		if settings := s.current[rule.Condition]; !settings.Empty() {
+			content.WriteString(fmt.Sprintf("%s: %s\n", rule.Condition, describeSigning(settings)))
+		}
+	}
+	content.WriteString("\n")
+	content.WriteString(GetFormStyle(m.width).Render(s.form.view()))
+	content.WriteString("\n")
+
+	target := "全局配置"
+	if condition := s.signingTarget(); condition != "" {
+		target = "includeIf \"" + condition + "\""
+		if _, ok := s.rule(condition); !ok {
+			target += "（新建规则）"
+		}
+	}
+	content.WriteString(fmt.Sprintf("写入: %s\n", target))
+	if email := s.signingEmail(); email != "" {
+		content.WriteString(fmt.Sprintf("签名者: %s\n", email))
+	}
+	if s.autoSign {
+		content.WriteString("自动签名: 开启（commit.gpgSign、tag.gpgSign）\n")
+	} else {
+		content.WriteString("自动签名: 关闭（需要时使用 git commit -S）\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(m.renderMessages())
+	content.WriteString(helpStyle.Render("Tab: 切换字段 • Ctrl+G: 切换自动签名 • Ctrl+X: 移除该处的签名设置 • Ctrl+O: 编辑 allowed_signers • Enter: 保存 • Esc: 返回"))
+	return content.String()
+}
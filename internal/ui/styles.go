@@ -75,6 +75,12 @@ formStyle = lipgloss.NewStyle().
 			Padding(0, 2).
 			Margin(0, 1)
 
+	// 下拉建议样式
+	dropdownStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(mutedColor).
+			MarginLeft(16)
+
 	// 确认对话框样式
 	confirmDialogStyle = lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
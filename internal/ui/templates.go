@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// templatePicker 保存平台模板选择视图的状态
+type templatePicker struct {
+	templates   []config.HostTemplate
+	cursor      int
+	returnState ViewState
+}
+
+// openTemplatePicker 在添加表单中打开平台模板选择视图
+func (m Model) openTemplatePicker() (tea.Model, tea.Cmd) {
+	templates, err := config.LoadTemplates()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.templatePicker = templatePicker{templates: templates, returnState: m.state}
+	m.err = nil
+	m.state = TemplatePickerView
+	return m, nil
+}
+
+// applyTemplate 用模板预填表单：Host 为空时填入别名前缀，自建服务器保留已填写的 HostName
+func (m *Model) applyTemplate(t config.HostTemplate) {
+	if m.form.inputs[0].Value() == "" && t.Alias != "" {
+		m.form.inputs[0].SetValue(t.Alias + "-")
+	}
+	if !t.SelfHosted() {
+		m.form.inputs[1].SetValue(t.HostName)
+	}
+	if t.User != "" {
+		m.form.inputs[2].SetValue(t.User)
+	}
+	port := t.Port
+	if port == config.DefaultPort {
+		port = ""
+	}
+	m.form.inputs[3].SetValue(port)
+	for i := range m.form.inputs {
+		m.form.inputs[i].CursorEnd()
+	}
+	m.refreshSuggestions()
+}
+
+// updateTemplatePickerView 更新平台模板选择视图
+func (m Model) updateTemplatePickerView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.templatePicker
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = p.returnState
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.templates)-1 {
+			p.cursor++
+		}
+	case "enter":
+		m.applyTemplate(p.templates[p.cursor])
+		m.state = p.returnState
+	}
+	return m, nil
+}
+
+// templatePickerView 渲染平台模板选择视图
+func (m Model) templatePickerView() string {
+	var content strings.Builder
+	p := m.templatePicker
+
+	content.WriteString(titleStyle.Render("选择平台模板"))
+	content.WriteString("\n\n")
+	content.WriteString(renderTemplateChoices(p.templates, p.cursor))
+	content.WriteString("\n")
+	if path, err := config.TemplatesPath(); err == nil {
+		content.WriteString(helpStyle.Render("自定义模板: " + config.ContractPath(path)))
+		content.WriteString("\n")
+	}
+	content.WriteString(m.renderMessages())
+	content.WriteString(helpStyle.Render("↑/↓: 选择 • Enter: 填入表单 • Esc: 返回"))
+	return content.String()
+}
+
+// renderTemplateChoices 渲染模板列表，每行显示名称、地址、用户和说明
+func renderTemplateChoices(templates []config.HostTemplate, cursor int) string {
+	var content strings.Builder
+	for i, t := range templates {
+		var details []string
+		details = append(details, t.Address())
+		if t.User != "" {
+			details = append(details, "User "+t.User)
+		}
+		if t.Description != "" {
+			details = append(details, t.Description)
+		}
+		detail := helpStyle.Render("  " + strings.Join(details, " • "))
+		if i == cursor {
+			content.WriteString(focusedStyle.Render("> "+t.Name) + detail)
+		} else {
+			content.WriteString(fmt.Sprintf("  %s%s", t.Name, detail))
+		}
+		content.WriteString("\n")
+	}
+	return content.String()
+}
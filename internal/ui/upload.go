@@ -0,0 +1,345 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/forge"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+)
+
+// 上传表单中各字段的位置
+const (
+	uploadFieldURL = iota
+	uploadFieldToken
+	uploadFieldRepo
+	uploadFieldTitle
+)
+
+// uploadMsg 列出或上传公钥完成后的消息，run 标识发起请求的那一次运行
+type uploadMsg struct {
+	run     int
+	keys    []forge.RemoteKey
+	repo    string
+	added   bool
+	existed bool
+	err     error
+}
+
+// uploadState 保存公钥上传视图的状态
+type uploadState struct {
+	host        config.SSHHost
+	pub         ssh.PublicKey
+	line        string
+	kind        forge.Kind
+	readOnly    bool
+	form        inputForm
+	running     bool
+	cancel      context.CancelFunc
+	remote      []forge.RemoteKey
+	remoteRepo  string
+	listed      bool
+	local       map[string]string
+	returnState ViewState
+	// run 在每次开始请求时递增，用于丢弃已取消的请求迟到的结果
+	run int
+}
+
+// fingerprint 返回要上传的公钥指纹
+func (u uploadState) fingerprint() string {
+	return ssh.FingerprintSHA256(u.pub)
+}
+
+// openUpload 对选中主机打开公钥上传视图
+func (m Model) openUpload() (tea.Model, tea.Cmd) {
+	host, ok := m.selectedHost()
+	if !ok {
+		return m, nil
+	}
+	return m.openUploadFor(host)
+}
+
+// openUploadFor 打开指定主机的公钥上传视图，根据 HostName 猜测平台和 API 地址
+func (m Model) openUploadFor(host config.SSHHost) (tea.Model, tea.Cmd) {
+	if host.IdentityFile == "" {
+		m.err = fmt.Errorf("%s 没有配置 IdentityFile", host.Host)
+		return m, nil
+	}
+	pub, comment, err := keys.PublicKey(config.ExpandPath(host.IdentityFile))
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	line, err := keys.EncodePublicKey(pub, comment, keys.ExportAuthorizedKeys)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	hostname, _ := host.Target()
+	kind, _ := forge.DetectKind(hostname)
+	title := host.Host
+	if name, err := os.Hostname(); err == nil {
+		title = fmt.Sprintf("%s (%s)", host.Host, name)
+	}
+	m.upload = uploadState{
+		host:        host,
+		pub:         pub,
+		line:        line,
+		kind:        kind,
+		readOnly:    true,
+		local:       localFingerprints(),
+		returnState: m.state,
+		run:         m.upload.run,
+	}
+	m.upload.form = newInputForm(
+		[]string{"API 地址:", "令牌:", "仓库:", "标题:"},
+		[]textinput.Model{
+			newTextInput("例如: https://api.github.com", forge.DefaultBaseURL(kind, hostname), 50),
+			newPasswordInput("个人访问令牌"),
+			newTextInput("留空上传到账户；owner/repo 添加为部署密钥", "", 50),
+			newTextInput("平台上显示的名称", title, 50),
+		},
+	)
+	m.upload.form.inputs[uploadFieldToken].SetValue(envToken(kind))
+	m.err = nil
+	m.status = ""
+	m.state = UploadView
+	return m, textinput.Blink
+}
+
+// envToken 从平台常用的环境变量中读取令牌
+func envToken(kind forge.Kind) string {
+	for _, name := range kind.TokenEnv() {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// localFingerprints 返回 ~/.ssh 中私钥指纹到路径的映射，用于标记平台上哪些密钥在本机
+func localFingerprints() map[string]string {
+	result := map[string]string{}
+	dir, err := config.SSHDir()
+	if err != nil {
+		return result
+	}
+	infos, err := keys.Scan(dir)
+	if err != nil {
+		return result
+	}
+	for _, info := range infos {
+		if info.Fingerprint != "" {
+			result[info.Fingerprint] = config.ContractPath(info.Path)
+		}
+	}
+	return result
+}
+
+// uploadClient 根据表单创建 API 客户端
+func (m Model) uploadClient() (*forge.Client, error) {
+	u := m.upload
+	baseURL := u.form.value(uploadFieldURL)
+	token := u.form.value(uploadFieldToken)
+	if baseURL == "" {
+		return nil, fmt.Errorf("请填写 API 地址")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("请填写访问令牌（也可以通过 %s 环境变量提供）", strings.Join(u.kind.TokenEnv(), " 或 "))
+	}
+	return forge.NewClient(u.kind, baseURL, token), nil
+}
+
+// runUpload 在后台列出平台上的密钥；upload 为 true 时公钥不存在则上传，已存在时不重复添加
+func runUpload(ctx context.Context, run int, client *forge.Client, repo, title, line, fingerprint string, readOnly, upload bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := uploadMsg{run: run, repo: repo}
+		msg.keys, msg.err = client.ListKeys(ctx, repo)
+		if msg.err != nil || !upload {
+			return msg
+		}
+		if _, ok := forge.FindKey(msg.keys, fingerprint); ok {
+			msg.existed = true
+			return msg
+		}
+		if _, msg.err = client.AddKey(ctx, repo, title, line, readOnly); msg.err != nil {
+			return msg
+		}
+		msg.added = true
+		msg.keys, msg.err = client.ListKeys(ctx, repo)
+		return msg
+	}
+}
+
+// startUpload 开始列出或上传
+func (m Model) startUpload(upload bool) (tea.Model, tea.Cmd) {
+	u := &m.upload
+	if u.running {
+		return m, nil
+	}
+	client, err := m.uploadClient()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	title := u.form.value(uploadFieldTitle)
+	if upload && title == "" {
+		m.err = fmt.Errorf("请填写标题")
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	u.running = true
+	u.cancel = cancel
+	u.run++
+	m.err = nil
+	m.status = ""
+	return m, runUpload(ctx, u.run, client, u.form.value(uploadFieldRepo), title, u.line, u.fingerprint(), u.readOnly, upload)
+}
+
+// handleUploadResult 处理列出或上传的结果；用户已离开视图或结果来自之前取消的请求时丢弃
+func (m Model) handleUploadResult(msg uploadMsg) (tea.Model, tea.Cmd) {
+	u := &m.upload
+	if m.state != UploadView || !u.running || msg.run != u.run {
+		return m, nil
+	}
+	u.running = false
+	u.cancel()
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	u.remote, u.remoteRepo, u.listed = msg.keys, msg.repo, true
+
+	target := "账户"
+	if msg.repo != "" {
+		target = msg.repo + " 的部署密钥"
+	}
+	switch {
+	case msg.added:
+		m.status = fmt.Sprintf("已上传到 %s %s", u.kind, target)
+	case msg.existed:
+		m.status = fmt.Sprintf("%s %s中已有该公钥", u.kind, target)
+	default:
+		m.status = fmt.Sprintf("%s %s中有 %d 个公钥", u.kind, target, len(msg.keys))
+	}
+	return m, nil
+}
+
+// updateUploadView 更新公钥上传视图
+func (m Model) updateUploadView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	u := &m.upload
+	switch msg.String() {
+	case "ctrl+c":
+		if u.running {
+			u.cancel()
+		}
+		return m, tea.Quit
+	case "esc":
+		if u.running {
+			u.cancel()
+			u.running = false
+			return m, nil
+		}
+		m.state = u.returnState
+		m.err = nil
+		return m, nil
+	case "ctrl+t":
+		// 切换平台时更新默认 API 地址，令牌为空时从环境变量读取
+		for i, kind := range forge.Kinds {
+			if kind == u.kind {
+				u.kind = forge.Kinds[(i+1)%len(forge.Kinds)]
+				break
+			}
+		}
+		hostname, _ := u.host.Target()
+		u.form.inputs[uploadFieldURL].SetValue(forge.DefaultBaseURL(u.kind, hostname))
+		if u.form.value(uploadFieldToken) == "" {
+			u.form.inputs[uploadFieldToken].SetValue(envToken(u.kind))
+		}
+		u.remote, u.listed = nil, false
+		return m, nil
+	case "ctrl+r":
+		u.readOnly = !u.readOnly
+		return m, nil
+	case "ctrl+l":
+		return m.startUpload(false)
+	}
+
+	submit, cmd := u.form.update(msg)
+	if !submit {
+		return m, cmd
+	}
+	return m.startUpload(true)
+}
+
+// uploadView 渲染公钥上传视图
+func (m Model) uploadView() string {
+	var content strings.Builder
+	u := m.upload
+
+	content.WriteString(titleStyle.Render(fmt.Sprintf("上传公钥: %s", u.host.Host)))
+	content.WriteString("\n\n")
+	content.WriteString(fmt.Sprintf("%s %s  %s\n", keys.KeyTypeName(u.pub.Type()), u.fingerprint(), u.host.IdentityFile))
+	content.WriteString(fmt.Sprintf("平台: %s\n", u.kind))
+	if repo := u.form.value(uploadFieldRepo); repo != "" {
+		access := "只读"
+		if !u.readOnly {
+			access = "可推送"
+		}
+		content.WriteString(fmt.Sprintf("目标: %s 的部署密钥（%s）\n", repo, access))
+	} else {
+		content.WriteString("目标: 账户密钥\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(GetFormStyle(m.width).Render(u.form.view()))
+	content.WriteString("\n")
+
+	if u.running {
+		content.WriteString("正在请求...\n\n")
+		content.WriteString(helpStyle.Render("Esc: 取消"))
+		return content.String()
+	}
+
+	if u.listed {
+		target := "账户中的公钥"
+		if u.remoteRepo != "" {
+			target = u.remoteRepo + " 的部署密钥"
+		}
+		content.WriteString(fmt.Sprintf("%s:\n", target))
+		if len(u.remote) == 0 {
+			content.WriteString(helpStyle.Render("  （无）"))
+			content.WriteString("\n")
+		}
+		for _, key := range u.remote {
+			line := fmt.Sprintf("  %s  %s", key.Title, key.Fingerprint)
+			if key.Repo != "" {
+				if key.ReadOnly {
+					line += "  只读"
+				} else {
+					line += "  可推送"
+				}
+			}
+			switch {
+			case key.Fingerprint == u.fingerprint():
+				content.WriteString(successStyle.Render(line + "  ← 当前密钥"))
+			case u.local[key.Fingerprint] != "":
+				content.WriteString(line + helpStyle.Render("  本机 "+u.local[key.Fingerprint]))
+			default:
+				content.WriteString(line)
+			}
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString(m.renderMessages())
+	content.WriteString(helpStyle.Render("Tab: 切换字段 • Ctrl+T: 切换平台 • Ctrl+R: 部署密钥只读/可推送 • Ctrl+L: 列出已有公钥 • Enter: 上传 • Esc: 返回"))
+	return content.String()
+}
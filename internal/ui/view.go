@@ -18,6 +18,46 @@ func (m Model) View() string {
 		return m.editView()
 	case DeleteConfirmView:
 		return m.deleteConfirmView()
+	case FilePickerView:
+		return m.filePickerView()
+	case KeysView:
+		return m.keysView()
+	case ExportView:
+		return m.exportView()
+	case HostDetailView:
+		return m.hostDetailView()
+	case PassphraseView:
+		return m.passphraseView()
+	case SignView:
+		return m.signView()
+	case AgentView:
+		return m.agentView()
+	case KnownHostsView:
+		return m.knownHostsView()
+	case ConnTestView:
+		return m.connTestView()
+	case RepoCheckView:
+		return m.repoCheckView()
+	case RemotesView:
+		return m.remotesView()
+	case WorkspaceView:
+		return m.workspaceView()
+	case IdentityView:
+		return m.identityView()
+	case InsteadOfView:
+		return m.insteadOfView()
+	case WizardView:
+		return m.wizardView()
+	case TemplatePickerView:
+		return m.templatePickerView()
+	case UploadView:
+		return m.uploadView()
+	case DeployKeysView:
+		return m.deployKeysView()
+	case SigningView:
+		return m.signingView()
+	case AllowedSignersView:
+		return m.allowedSignersView()
 	default:
 		return "未知状态"
 	}
@@ -31,17 +71,33 @@ func (m Model) listView() string {
 	content.WriteString(m.list.View())
 	content.WriteString("\n")
 
-	// 错误信息
-	if m.err != nil {
-		content.WriteString(errorStyle.Render(fmt.Sprintf("错误: %s", m.err.Error())))
-		content.WriteString("\n")
-	}
+	// 状态和错误信息
+	content.WriteString(m.renderMessages())
 
 	// 帮助信息
 	helpText := []string{
 		"a/n: 添加新配置",
+		"N: 添加账户向导",
+		"Enter: 详情",
 		"e: 编辑配置",
 		"d/x: 删除配置",
+		"p: 导出公钥",
+		"U: 上传公钥到平台",
+		"D: 批量生成部署密钥",
+		"P: 设置口令",
+		"S: 签发证书",
+		"K: 密钥清单",
+		"V: allowed_signers",
+		"A: ssh-agent",
+		"H: known_hosts",
+		"s: 打开会话",
+		"T: 连接测试",
+		"L: 检查仓库访问",
+		"R: 改写仓库远程地址",
+		"W: 扫描工作区",
+		"G: 目录身份",
+		"I: 地址改写规则",
+		"c: 检查全部连通性",
 		"q: 退出",
 	}
 	content.WriteString(helpStyle.Render(strings.Join(helpText, " • ")))
@@ -76,8 +132,11 @@ content.WriteString(GetFormStyle(m.width).Render(formContent))
 	// 帮助信息
 	content.WriteString("\n\n")
 	helpText := []string{
-		"Tab: 下一个字段",
+		"Tab: 补全/下一个字段",
 		"Shift+Tab: 上一个字段",
+		"Ctrl+N/P: 选择建议",
+		"Ctrl+F: 选择密钥文件",
+		"Ctrl+T: 平台模板",
 		"Enter: 提交",
 		"Esc: 取消",
 	}
@@ -108,6 +167,10 @@ func (m Model) renderForm() string {
 
 	// IdentityFile 字段
 	form.WriteString(m.renderFormField("IdentityFile:", m.form.inputs[4], 4))
+	form.WriteString("\n")
+
+	// CertificateFile 字段
+	form.WriteString(m.renderFormField("CertificateFile:", m.form.inputs[5], 5))
 	form.WriteString("\n\n")
 
 	// 提交按钮
@@ -136,7 +199,13 @@ func (m Model) renderFormField(label string, input interface{}, index int) strin
 
 	// 输入框
 	if textInput, ok := input.(textinput.Model); ok {
-		field.WriteString(textInput.View())
+		field.WriteString(inputView(textInput))
+	}
+
+	// 下拉建议
+	if m.form.focusIndex == index && len(m.form.suggestions) > 0 {
+		field.WriteString("\n")
+		field.WriteString(m.renderSuggestions())
 	}
 
 	return field.String()
@@ -169,8 +238,10 @@ content.WriteString(GetFormStyle(m.width).Render(formContent))
 	// 帮助信息
 	content.WriteString("\n\n")
 	helpText := []string{
-		"Tab: 下一个字段",
+		"Tab: 补全/下一个字段",
 		"Shift+Tab: 上一个字段",
+		"Ctrl+N/P: 选择建议",
+		"Ctrl+F: 选择密钥文件",
 		"Enter: 保存修改",
 		"Esc: 取消",
 	}
@@ -193,13 +264,18 @@ func (m Model) deleteConfirmView() string {
 		if host.Port != "" {
 			portInfo = fmt.Sprintf("Port: %s\n", host.Port)
 		}
+		certInfo := ""
+		if host.CertificateFile != "" {
+			certInfo = fmt.Sprintf("CertificateFile: %s\n", host.CertificateFile)
+		}
 		dialogContent := fmt.Sprintf(
 			"确定要删除以下 SSH 配置吗？\n\n"+
 				"Host: %s\n"+
 				"HostName: %s\n"+
 				"User: %s\n"+
 				"%s"+
-				"IdentityFile: %s\n\n"+
+				"IdentityFile: %s\n"+
+				"%s\n"+
 				"此操作无法撤销！\n\n"+
 				"[Y] 确认删除    [N] 取消",
 			host.Host,
@@ -207,6 +283,7 @@ func (m Model) deleteConfirmView() string {
 			host.User,
 			portInfo,
 			host.IdentityFile,
+			certInfo,
 		)
 
 		content.WriteString(confirmDialogStyle.Render(dialogContent))
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/allanpk716/git_ssh_tui/internal/knownhosts"
+)
+
+// 官方指纹目录在首次使用时读取，运行期间不再变化
+var (
+	catalogOnce sync.Once
+	catalog     *knownhosts.Catalog
+	catalogErr  error
+)
+
+// pinnedCatalog 返回官方指纹目录
+func pinnedCatalog() (*knownhosts.Catalog, error) {
+	catalogOnce.Do(func() {
+		catalog, catalogErr = knownhosts.LoadCatalog()
+	})
+	return catalog, catalogErr
+}
+
+// loadKnownHosts 读取默认的 known_hosts，供一次列表刷新中的所有主机共用；无法读取时返回 nil
+func loadKnownHosts() *knownhosts.File {
+	path, err := knownhosts.DefaultPath()
+	if err != nil {
+		return nil
+	}
+	file, err := knownhosts.Load(path)
+	if err != nil {
+		return nil
+	}
+	return file
+}
+
+// hostWarnings 检查主机配置引用的密钥和证书，返回需要在列表中提示的问题
+func hostWarnings(host config.SSHHost, knownHosts *knownhosts.File) []string {
+	warnings := append(identityWarnings(host), certificateWarnings(host)...)
+	return append(warnings, pinnedWarnings(host, knownHosts)...)
+}
+
+// identityWarnings 检查 IdentityFile 的格式和强度
+func identityWarnings(host config.SSHHost) []string {
+	if host.IdentityFile == "" {
+		return nil
+	}
+	if valid, warning := config.ValidateIdentityFile(host.IdentityFile); !valid {
+		return []string{warning}
+	}
+
+	info, err := keys.Inspect(config.ExpandPath(host.IdentityFile))
+	if err != nil {
+		return []string{err.Error()}
+	}
+	return keys.Audit(info)
+}
+
+// certificateWarnings 检查主机使用的证书是否过期或即将过期
+func certificateWarnings(host config.SSHHost) []string {
+	cert, err := hostCertificate(host)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if cert == nil {
+		return nil
+	}
+	return cert.Check(time.Now())
+}
+
+// pinnedWarnings 对比 known_hosts 与官方指纹目录，主机密钥不一致时可能遭遇了中间人攻击或目录已过时
+func pinnedWarnings(host config.SSHHost, knownHosts *knownhosts.File) []string {
+	catalog, err := pinnedCatalog()
+	if err != nil {
+		return []string{err.Error()}
+	}
+	name, port := hostAddress(host)
+	provider, ok := catalog.Lookup(name, port)
+	if !ok || knownHosts == nil {
+		return nil
+	}
+
+	var warnings []string
+	for _, entry := range provider.Mismatches(knownHosts.Find(name, port)) {
+		warnings = append(warnings, fmt.Sprintf("known_hosts 第 %d 行的 %s 主机密钥与 %s 公布的指纹不一致",
+			entry.Line, keys.KeyTypeName(entry.Key.Type()), provider.Name))
+	}
+	return warnings
+}
+
+// hostCertificate 返回主机使用的证书。未配置 CertificateFile 时与 OpenSSH 一样尝试 IdentityFile-cert.pub，不存在则返回 nil
+func hostCertificate(host config.SSHHost) (*keys.CertInfo, error) {
+	if host.CertificateFile != "" {
+		return keys.ParseCertificate(config.ExpandPath(host.CertificateFile))
+	}
+	if host.IdentityFile == "" {
+		return nil, nil
+	}
+	path := keys.CertPathFor(config.ExpandPath(host.IdentityFile))
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+	return keys.ParseCertificate(path)
+}
+
+// newHostItem 创建列表项并计算其警告信息
+func newHostItem(host config.SSHHost, knownHosts *knownhosts.File) HostItem {
+	return HostItem{host: host, warnings: hostWarnings(host, knownHosts)}
+}
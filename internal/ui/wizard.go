@@ -0,0 +1,413 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/clipboard"
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/keys"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+)
+
+// wizardStep 表示多账户向导当前所在的步骤
+type wizardStep int
+
+const (
+	wizardStepProvider wizardStep = iota
+	wizardStepForm
+	wizardStepConfirm
+	wizardStepRunning
+	wizardStepDone
+)
+
+// wizardCustomTemplate 是向导中附加在模板之后的通用自建服务器选项
+var wizardCustomTemplate = config.HostTemplate{Name: "其他（自建服务器）", Alias: "git", User: "git"}
+
+// wizardKeyType 返回模板要求的密钥类型，未指定时使用 ED25519
+func wizardKeyType(t config.HostTemplate) string {
+	if t.KeyType == keys.GenerateRSA {
+		return keys.GenerateRSA
+	}
+	return keys.GenerateEd25519
+}
+
+// wizardLabelPattern 限制账户标签只能使用可以出现在别名和文件名中的字符
+var wizardLabelPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// wizardPlan 是确认前计算出的操作计划
+type wizardPlan struct {
+	host       config.SSHHost
+	keyPath    string
+	keyType    string
+	comment    string
+	passphrase string
+}
+
+// wizardKeyMsg 后台生成密钥完成后的消息
+type wizardKeyMsg struct {
+	pub ssh.PublicKey
+	err error
+}
+
+// wizardKeyName 返回向导生成的密钥类型的显示名称
+func wizardKeyName(keyType string) string {
+	if keyType == keys.GenerateRSA {
+		return fmt.Sprintf("RSA %d", keys.GenerateRSABits)
+	}
+	return strings.ToUpper(keyType)
+}
+
+// wizardState 保存多账户向导的状态
+type wizardState struct {
+	step      wizardStep
+	cursor    int
+	templates []config.HostTemplate
+	provider  config.HostTemplate
+	form      inputForm
+	fields    []string
+	plan      wizardPlan
+	results   []stepResult
+	publicKey string
+}
+
+// openWizard 打开多账户向导
+func (m Model) openWizard() (tea.Model, tea.Cmd) {
+	templates, err := config.LoadTemplates()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.wizard = wizardState{templates: append(templates, wizardCustomTemplate)}
+	m.err = nil
+	m.status = ""
+	m.state = WizardView
+	return m, nil
+}
+
+// openWizardForm 进入填写账户信息的步骤；自建服务器需要额外填写主机名和端口，模板没有指定 User 时需要填写用户名
+func (m *Model) openWizardForm() tea.Cmd {
+	w := &m.wizard
+	var labels, fields []string
+	var inputs []textinput.Model
+	add := func(field, label string, input textinput.Model) {
+		fields = append(fields, field)
+		labels = append(labels, label)
+		inputs = append(inputs, input)
+	}
+	if w.provider.SelfHosted() {
+		add("hostname", "主机名:", newTextInput("例如: git.example.com", "", 40))
+		add("port", "端口:", newTextInput(config.DefaultPort, w.provider.Port, 10))
+	}
+	if w.provider.User == "" {
+		add("user", "用户名:", newTextInput("服务器上的用户名", "", 30))
+	}
+	add("label", "账户标签:", newTextInput("例如: work、personal", "", 30))
+	add("alias", "别名:", newTextInput(fmt.Sprintf("留空使用 %s-<标签>", w.provider.Alias), "", 30))
+	add("comment", "密钥注释:", newTextInput("留空使用别名，常用邮箱", "", 40))
+	add("passphrase", "口令:", newPasswordInput("留空表示不设置口令"))
+	add("confirm", "确认口令:", newPasswordInput("再输入一次"))
+
+	w.form = newInputForm(labels, inputs)
+	w.fields = fields
+	w.step = wizardStepForm
+	return textinput.Blink
+}
+
+// field 返回表单中指定字段去除首尾空白后的内容，口令字段保留原样；字段不存在时返回空
+func (w wizardState) field(name string) string {
+	for i, field := range w.fields {
+		if field != name {
+			continue
+		}
+		if name == "passphrase" || name == "confirm" {
+			return w.form.inputs[i].Value()
+		}
+		return w.form.value(i)
+	}
+	return ""
+}
+
+// buildWizardPlan 校验表单并计算要生成的密钥和别名
+func (m Model) buildWizardPlan() (wizardPlan, error) {
+	w := m.wizard
+	hostname, port, user := w.provider.HostName, w.provider.Port, w.provider.User
+	if w.provider.SelfHosted() {
+		hostname, port = w.field("hostname"), w.field("port")
+		if hostname == "" {
+			return wizardPlan{}, fmt.Errorf("请填写主机名")
+		}
+	}
+	if port == config.DefaultPort {
+		port = ""
+	}
+	if user == "" {
+		user = w.field("user")
+		if user == "" {
+			return wizardPlan{}, fmt.Errorf("请填写用户名")
+		}
+	}
+	label := w.field("label")
+	alias := w.field("alias")
+	comment := w.field("comment")
+	passphrase := w.field("passphrase")
+
+	if !wizardLabelPattern.MatchString(label) {
+		return wizardPlan{}, fmt.Errorf("账户标签只能包含字母、数字、点、下划线和连字符")
+	}
+	if alias == "" {
+		alias = w.provider.Alias + "-" + label
+	}
+	if strings.ContainsAny(alias, "*?! \t") {
+		return wizardPlan{}, fmt.Errorf("别名不能包含通配符或空白")
+	}
+	if _, ok := m.sshConfig.FindHost(alias); ok {
+		return wizardPlan{}, fmt.Errorf("~/.ssh/config 中已有别名 %s", alias)
+	}
+	if comment == "" {
+		comment = alias
+	}
+	if passphrase != w.field("confirm") {
+		return wizardPlan{}, fmt.Errorf("两次输入的口令不一致")
+	}
+
+	sshDir, err := config.SSHDir()
+	if err != nil {
+		return wizardPlan{}, err
+	}
+	keyType := wizardKeyType(w.provider)
+	keyPath := filepath.Join(sshDir, fmt.Sprintf("id_%s_%s", keyType, alias))
+	if _, err := os.Stat(keyPath); err == nil {
+		return wizardPlan{}, fmt.Errorf("%s 已存在，请换一个标签或别名", config.ContractPath(keyPath))
+	}
+
+	return wizardPlan{
+		host: config.SSHHost{
+			Host:         alias,
+			HostName:     hostname,
+			User:         user,
+			Port:         port,
+			IdentityFile: config.ContractPath(keyPath),
+		},
+		keyPath:    keyPath,
+		keyType:    keyType,
+		comment:    comment,
+		passphrase: passphrase,
+	}, nil
+}
+
+// generateWizardKey 在后台生成密钥，RSA 4096 需要一两秒
+func generateWizardKey(plan wizardPlan) tea.Cmd {
+	return func() tea.Msg {
+		pub, err := keys.Generate(plan.keyPath, plan.keyType, plan.comment, []byte(plan.passphrase))
+		return wizardKeyMsg{pub: pub, err: err}
+	}
+}
+
+// handleWizardKey 密钥生成后添加别名并写入 known_hosts；生成失败时停止
+func (m Model) handleWizardKey(msg wizardKeyMsg) (tea.Model, tea.Cmd) {
+	w := &m.wizard
+	if m.state != WizardView || w.step != wizardStepRunning {
+		return m, nil
+	}
+	w.step = wizardStepDone
+	plan := w.plan
+	if msg.err != nil {
+		w.results = []stepResult{{detail: "生成密钥失败: " + msg.err.Error()}}
+		return m, nil
+	}
+	w.results = []stepResult{{ok: true, detail: fmt.Sprintf("已生成 %s 密钥 %s", wizardKeyName(plan.keyType), plan.host.IdentityFile)}}
+	w.publicKey, _ = keys.EncodePublicKey(msg.pub, plan.comment, keys.ExportAuthorizedKeys)
+
+	m.sshConfig.AddHost(plan.host)
+	if err := m.sshConfig.Save(); err != nil {
+		w.results = append(w.results, stepResult{detail: "写入 ~/.ssh/config 失败: " + err.Error()})
+		return m, nil
+	}
+	w.results = append(w.results, stepResult{ok: true, detail: fmt.Sprintf("已添加别名 %s → %s", plan.host.Host, plan.host.HostName)})
+	w.results = append(w.results, seedPinnedHostKeys(plan.host))
+	m.refreshList()
+	return m, nil
+}
+
+// updateWizardView 更新多账户向导
+func (m Model) updateWizardView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	w := &m.wizard
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch w.step {
+	case wizardStepProvider:
+		switch msg.String() {
+		case "esc", "q":
+			m.state = ListView
+			return m, nil
+		case "up", "k":
+			if w.cursor > 0 {
+				w.cursor--
+			}
+		case "down", "j":
+			if w.cursor < len(w.templates)-1 {
+				w.cursor++
+			}
+		case "enter":
+			w.provider = w.templates[w.cursor]
+			return m, m.openWizardForm()
+		}
+		return m, nil
+
+	case wizardStepForm:
+		if msg.String() == "esc" {
+			w.step = wizardStepProvider
+			m.err = nil
+			return m, nil
+		}
+		submit, cmd := w.form.update(msg)
+		if !submit {
+			return m, cmd
+		}
+		plan, err := m.buildWizardPlan()
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		w.plan = plan
+		w.step = wizardStepConfirm
+		m.err = nil
+		return m, nil
+
+	case wizardStepConfirm:
+		switch msg.String() {
+		case "y", "Y", "enter":
+			w.step = wizardStepRunning
+			return m, generateWizardKey(w.plan)
+		case "n", "N", "esc":
+			w.step = wizardStepForm
+			return m, textinput.Blink
+		}
+		return m, nil
+
+	case wizardStepRunning:
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "enter", "q":
+		m.state = ListView
+		m.err = nil
+		if w.publicKey != "" {
+			m.status = fmt.Sprintf("已添加 %s，上传公钥后即可使用", w.plan.host.Host)
+		}
+		return m, nil
+	case "c":
+		if w.publicKey == "" {
+			return m, nil
+		}
+		method, err := clipboard.Copy(w.publicKey)
+		if err != nil {
+			m.err = fmt.Errorf("复制失败: %w", err)
+			return m, nil
+		}
+		m.err = nil
+		m.status = fmt.Sprintf("已通过%s复制公钥", method)
+	case "t":
+		if _, ok := m.sshConfig.FindHost(w.plan.host.Host); ok {
+			m.status = ""
+			return m.startConnTest(w.plan.host)
+		}
+	case "u":
+		if _, ok := m.sshConfig.FindHost(w.plan.host.Host); ok {
+			return m.openUploadFor(w.plan.host)
+		}
+	}
+	return m, nil
+}
+
+// wizardView 渲染多账户向导
+func (m Model) wizardView() string {
+	var content strings.Builder
+	w := m.wizard
+
+	switch w.step {
+	case wizardStepProvider:
+		content.WriteString(titleStyle.Render("添加账户 1/4: 选择平台"))
+		content.WriteString("\n\n")
+		content.WriteString(renderTemplateChoices(w.templates, w.cursor))
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("↑/↓: 选择 • Enter: 下一步 • Esc: 返回"))
+
+	case wizardStepForm:
+		content.WriteString(titleStyle.Render("添加账户 2/4: " + w.provider.Name + " 账户信息"))
+		content.WriteString("\n\n")
+		content.WriteString(GetFormStyle(m.width).Render(w.form.view()))
+		content.WriteString("\n")
+		content.WriteString(fmt.Sprintf("将生成专用的 %s 密钥，别名使用 IdentitiesOnly yes\n\n", wizardKeyName(wizardKeyType(w.provider))))
+		content.WriteString(m.renderMessages())
+		content.WriteString(helpStyle.Render("Tab: 切换字段 • Enter: 下一步 • Esc: 重新选择平台"))
+
+	case wizardStepConfirm:
+		p := w.plan
+		content.WriteString(titleStyle.Render("添加账户 3/4: 确认"))
+		content.WriteString("\n\n")
+		content.WriteString(fmt.Sprintf("生成密钥: %s (%s)\n", config.ContractPath(p.keyPath), wizardKeyName(p.keyType)))
+		content.WriteString(fmt.Sprintf("密钥注释: %s\n", p.comment))
+		if p.passphrase == "" {
+			content.WriteString("口令: 不设置\n")
+		} else {
+			content.WriteString("口令: 已设置\n")
+		}
+		content.WriteString("\n添加到 ~/.ssh/config:\n")
+		content.WriteString(successStyle.Render(fmt.Sprintf("  Host %s\n      HostName %s\n      User %s", p.host.Host, p.host.HostName, p.host.User)))
+		content.WriteString("\n")
+		if p.host.Port != "" {
+			content.WriteString(successStyle.Render("      Port " + p.host.Port))
+			content.WriteString("\n")
+		}
+		content.WriteString(successStyle.Render(fmt.Sprintf("      IdentityFile %s\n      IdentitiesOnly yes", p.host.IdentityFile)))
+		content.WriteString("\n\n")
+		// 地址中的用户名会覆盖别名的 User，非 git 用户时省略
+		if p.host.User == "git" {
+			content.WriteString(fmt.Sprintf("克隆时使用: git@%s:<owner>/<repo>.git\n\n", p.host.Host))
+		} else {
+			content.WriteString(fmt.Sprintf("克隆时使用: ssh://%s/<project>\n\n", p.host.Host))
+		}
+		content.WriteString(helpStyle.Render("y/Enter: 执行 • n/Esc: 返回修改"))
+
+	case wizardStepRunning:
+		content.WriteString(titleStyle.Render("添加账户 3/4: 确认"))
+		content.WriteString("\n\n")
+		content.WriteString(fmt.Sprintf("正在生成 %s 密钥...\n", wizardKeyName(w.plan.keyType)))
+
+	case wizardStepDone:
+		content.WriteString(titleStyle.Render("添加账户 4/4: 上传公钥并测试"))
+		content.WriteString("\n\n")
+		content.WriteString(renderStepResults(w.results))
+		if w.publicKey != "" {
+			content.WriteString("\n公钥:\n")
+			content.WriteString(w.publicKey)
+			if w.provider.KeysURL != "" {
+				content.WriteString(fmt.Sprintf("\n把公钥添加到 %s 账户: %s\n", w.provider.Name, w.provider.KeysPage(w.plan.host.HostName)))
+			} else {
+				content.WriteString("\n把公钥添加到服务器上该账户的 SSH 密钥设置中\n")
+			}
+			if w.plan.passphrase != "" {
+				content.WriteString("私钥设置了口令，连接测试前请先在 ssh-agent 视图中加载\n")
+			}
+		}
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		if w.publicKey != "" {
+			content.WriteString(helpStyle.Render("c: 复制公钥 • u: 通过 API 上传 • t: 连接测试 • Enter/Esc: 完成"))
+		} else {
+			content.WriteString(helpStyle.Render("Enter/Esc: 返回"))
+		}
+	}
+	return content.String()
+}
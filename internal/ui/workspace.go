@@ -0,0 +1,348 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/allanpk716/git_ssh_tui/internal/config"
+	"github.com/allanpk716/git_ssh_tui/internal/gitconfig"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// workspaceScanMsg 工作区扫描完成后的消息
+type workspaceScanMsg struct {
+	root  string
+	repos []gitconfig.Repo
+	err   error
+}
+
+// workspaceRow 表示扫描结果中的一个远程地址及其实际使用的身份
+type workspaceRow struct {
+	repo     gitconfig.Repo
+	remote   gitconfig.RemoteURL
+	host     string
+	alias    string
+	identity string
+	status   string
+	flagged  bool
+}
+
+// workspaceState 保存工作区扫描视图的状态
+type workspaceState struct {
+	form        inputForm
+	running     bool
+	cancel      context.CancelFunc
+	root        string
+	repos       int
+	rows        []workspaceRow
+	visible     []workspaceRow
+	onlyFlagged bool
+	table       table.Model
+	returnState ViewState
+}
+
+// openWorkspace 打开工作区扫描视图，默认扫描 ~/src（不存在时为主目录）
+func (m Model) openWorkspace() (tea.Model, tea.Cmd) {
+	value := "~"
+	if info, err := os.Stat(config.ExpandPath("~/src")); err == nil && info.IsDir() {
+		value = "~/src"
+	}
+	input := newTextInput("工作区目录", value, 50)
+	input.CursorEnd()
+	m.workspace = workspaceState{
+		form:        newInputForm([]string{"目录:"}, []textinput.Model{input}),
+		table:       newWorkspaceTable(),
+		returnState: m.state,
+	}
+	m.err = nil
+	m.status = ""
+	m.state = WorkspaceView
+	return m, textinput.Blink
+}
+
+// newWorkspaceTable 创建扫描结果表格
+func newWorkspaceTable() table.Model {
+	t := table.New(table.WithFocused(true))
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.Foreground(secondaryColor).Bold(true)
+	styles.Selected = styles.Selected.Foreground(primaryColor).Bold(true)
+	t.SetStyles(styles)
+	return t
+}
+
+// scanWorkspace 在后台扫描目录下的仓库
+func scanWorkspace(ctx context.Context, root string) tea.Cmd {
+	return func() tea.Msg {
+		repos, err := gitconfig.ScanRepos(ctx, root, gitconfig.DefaultScanDepth)
+		return workspaceScanMsg{root: root, repos: repos, err: err}
+	}
+}
+
+// handleWorkspaceScan 处理扫描结果；用户已取消时丢弃
+func (m Model) handleWorkspaceScan(msg workspaceScanMsg) (tea.Model, tea.Cmd) {
+	w := &m.workspace
+	if m.state != WorkspaceView || !w.running {
+		return m, nil
+	}
+	w.running = false
+	w.cancel()
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+
+	w.root = msg.root
+	w.repos = len(msg.repos)
+	w.rows = nil
+	for _, repo := range msg.repos {
+		if len(repo.Remotes) == 0 {
+			w.rows = append(w.rows, workspaceRow{repo: repo, status: "没有远程仓库"})
+			continue
+		}
+		for _, remote := range repo.Remotes {
+			w.rows = append(w.rows, m.workspaceRow(repo, remote))
+		}
+	}
+	m.refreshWorkspaceTable()
+	return m, nil
+}
+
+// workspaceRow 判断远程地址实际会使用哪个别名和 IdentityFile。
+// 地址直接使用主机名时，ssh 不会读取指向该主机的其他别名，可能用错账户，因此需要标记。
+func (m Model) workspaceRow(repo gitconfig.Repo, remote gitconfig.RemoteURL) workspaceRow {
+	row := workspaceRow{repo: repo, remote: remote, host: "-", alias: "-", identity: "-"}
+	parsed, err := gitconfig.ParseRemote(remote.URL)
+	if err != nil {
+		row.status = "非 SSH 地址"
+		return row
+	}
+	row.host = parsed.Host
+
+	host, ok := m.sshConfig.FindHost(parsed.Host)
+	matches := m.sshConfig.MatchHosts(parsed.Host, parsed.Port, parsed.User)
+	switch {
+	case ok && host.HostName != "" && host.HostName != host.Host:
+		hostname, _ := host.Target()
+		row.host = hostname
+		row.alias = host.Host
+		row.status = "✓ 使用别名"
+	case ok:
+		// 与主机名同名的条目，同一主机还有其他别名时可能用错账户
+		row.alias = host.Host
+		row.status = "✓"
+		if others := otherAliases(matches, host.Host); len(others) > 0 {
+			row.status = "! 另有别名 " + strings.Join(others, ", ")
+			row.flagged = true
+		}
+	case len(matches) == 0:
+		row.status = "✗ 没有匹配的别名"
+		row.flagged = true
+	case len(matches) == 1:
+		row.status = "! 未使用别名 " + matches[0].Host
+		row.flagged = true
+	default:
+		row.status = "! 多个别名 " + strings.Join(otherAliases(matches, ""), ", ")
+		row.flagged = true
+	}
+
+	if ok && host.IdentityFile != "" {
+		row.identity = host.IdentityFile
+	} else {
+		row.identity = "（默认密钥）"
+	}
+	return row
+}
+
+// otherAliases 返回除 exclude 之外的别名名称
+func otherAliases(hosts []config.SSHHost, exclude string) []string {
+	var names []string
+	for _, host := range hosts {
+		if host.Host != exclude {
+			names = append(names, host.Host)
+		}
+	}
+	return names
+}
+
+// refreshWorkspaceTable 按筛选条件和窗口大小重建表格
+func (m *Model) refreshWorkspaceTable() {
+	w := &m.workspace
+	w.visible = nil
+	for _, row := range w.rows {
+		if !w.onlyFlagged || row.flagged {
+			w.visible = append(w.visible, row)
+		}
+	}
+
+	width := m.width
+	if width <= 0 {
+		width = 120
+	}
+	// 仓库、远程、主机、别名、IdentityFile 使用固定比例，其余宽度留给状态
+	cols := []int{width * 24 / 100, 10, width * 16 / 100, width * 12 / 100, width * 18 / 100}
+	rest := width - 2*len(cols) - 2
+	for _, c := range cols {
+		rest -= c
+	}
+	if rest < 16 {
+		rest = 16
+	}
+	w.table.SetColumns([]table.Column{
+		{Title: "仓库", Width: cols[0]},
+		{Title: "远程", Width: cols[1]},
+		{Title: "主机", Width: cols[2]},
+		{Title: "别名", Width: cols[3]},
+		{Title: "IdentityFile", Width: cols[4]},
+		{Title: "状态", Width: rest},
+	})
+
+	rows := make([]table.Row, 0, len(w.visible))
+	for _, row := range w.visible {
+		name := row.remote.Name
+		if row.remote.Key == "pushurl" {
+			name += " (push)"
+		}
+		rows = append(rows, table.Row{w.repoName(row.repo), name, row.host, row.alias, row.identity, row.status})
+	}
+	w.table.SetRows(rows)
+	height := m.height - 10
+	if height < 5 {
+		height = 5
+	}
+	w.table.SetHeight(height)
+	w.table.SetWidth(width)
+	if w.table.Cursor() >= len(rows) {
+		w.table.SetCursor(len(rows) - 1)
+	}
+	if w.table.Cursor() < 0 && len(rows) > 0 {
+		w.table.SetCursor(0)
+	}
+}
+
+// repoName 返回仓库相对于扫描目录的路径
+func (w workspaceState) repoName(repo gitconfig.Repo) string {
+	rel, err := filepath.Rel(w.root, repo.Path)
+	if err != nil || rel == "." {
+		return config.ContractPath(repo.Path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// updateWorkspaceView 更新工作区扫描视图
+func (m Model) updateWorkspaceView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	w := &m.workspace
+	switch msg.String() {
+	case "ctrl+c":
+		if w.running {
+			w.cancel()
+		}
+		return m, tea.Quit
+	case "esc":
+		switch {
+		case w.running:
+			w.cancel()
+			w.running = false
+		case w.rows != nil:
+			w.rows, w.visible = nil, nil
+			m.err = nil
+			return m, textinput.Blink
+		default:
+			m.state = w.returnState
+			m.err = nil
+		}
+		return m, nil
+	}
+	if w.running {
+		return m, nil
+	}
+
+	// 输入扫描目录
+	if w.rows == nil {
+		submit, cmd := w.form.update(msg)
+		if !submit {
+			return m, cmd
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		w.running = true
+		w.cancel = cancel
+		m.err = nil
+		return m, scanWorkspace(ctx, config.ExpandPath(w.form.value(0)))
+	}
+
+	switch msg.String() {
+	case "f":
+		w.onlyFlagged = !w.onlyFlagged
+		w.table.SetCursor(0)
+		m.refreshWorkspaceTable()
+		return m, nil
+	case "r":
+		ctx, cancel := context.WithCancel(context.Background())
+		w.running = true
+		w.cancel = cancel
+		m.err = nil
+		return m, scanWorkspace(ctx, w.root)
+	case "enter":
+		if cursor := w.table.Cursor(); cursor >= 0 && cursor < len(w.visible) {
+			return m.openRemotes(w.visible[cursor].repo.Path)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	w.table, cmd = w.table.Update(msg)
+	return m, cmd
+}
+
+// workspaceView 渲染工作区扫描视图
+func (m Model) workspaceView() string {
+	var content strings.Builder
+	w := m.workspace
+
+	content.WriteString(titleStyle.Render("工作区仓库扫描"))
+	content.WriteString("\n\n")
+
+	if w.rows == nil {
+		content.WriteString(GetFormStyle(m.width).Render(w.form.view()))
+		content.WriteString("\n")
+		content.WriteString(m.renderMessages())
+		if w.running {
+			content.WriteString("正在扫描...\n\n")
+			content.WriteString(helpStyle.Render("Esc: 取消"))
+			return content.String()
+		}
+		content.WriteString(helpStyle.Render("Enter: 扫描 • Esc: 返回"))
+		return content.String()
+	}
+
+	flagged := 0
+	for _, row := range w.rows {
+		if row.flagged {
+			flagged++
+		}
+	}
+	summary := fmt.Sprintf("%s: %d 个仓库，%d 个远程地址", config.ContractPath(w.root), w.repos, len(w.rows))
+	content.WriteString(summary)
+	if flagged > 0 {
+		content.WriteString("，")
+		content.WriteString(warningStyle.Render(fmt.Sprintf("%d 个可能使用错误的账户", flagged)))
+	}
+	if w.onlyFlagged {
+		content.WriteString(helpStyle.Render("（只显示需要注意的）"))
+	}
+	content.WriteString("\n\n")
+	content.WriteString(w.table.View())
+	content.WriteString("\n\n")
+	content.WriteString(m.renderMessages())
+	if w.running {
+		content.WriteString("正在扫描...\n\n")
+		content.WriteString(helpStyle.Render("Esc: 取消"))
+		return content.String()
+	}
+	content.WriteString(helpStyle.Render("↑/↓: 选择 • Enter: 改写远程地址 • f: 只看需要注意的 • r: 重新扫描 • Esc: 重新选择目录"))
+	return content.String()
+}